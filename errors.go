@@ -0,0 +1,105 @@
+package structure_websites
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrorCode categorizes an Error returned by this package so callers can
+// branch on failure kind instead of parsing an opaque driver error string.
+type ErrorCode string
+
+const (
+	// ErrDuplicate means the write violated a unique index (Mongo E11000).
+	ErrDuplicate ErrorCode = "duplicate"
+	// ErrNotFound means the requested document does not exist.
+	ErrNotFound ErrorCode = "not_found"
+	// ErrInvalidID means a provided _id was not a valid hex ObjectID.
+	ErrInvalidID ErrorCode = "invalid_id"
+	// ErrDecode means a document could not be decoded from BSON.
+	ErrDecode ErrorCode = "decode"
+	// ErrTransient means the failure is likely to succeed on retry (network
+	// fault, timeout, transient transaction conflict).
+	ErrTransient ErrorCode = "transient"
+	// ErrValidation means the caller's input failed a precondition check
+	// before any Mongo call was made.
+	ErrValidation ErrorCode = "validation"
+)
+
+// Error wraps an underlying cause with a stable Code, so callers (including
+// the GraphQL resolver) can distinguish failure kinds with errors.As instead
+// of matching on err.Error() text.
+type Error struct {
+	Code ErrorCode
+	Err  error
+}
+
+// NewError wraps err under code. err may be nil.
+func NewError(code ErrorCode, err error) *Error {
+	return &Error{Code: code, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return string(e.Code)
+	}
+	return fmt.Sprintf("%s: %v", e.Code, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// mapMongoError classifies a driver error returned by a structure_websites
+// Mongo call into a stable Error. It checks, in order: ErrNoDocuments,
+// duplicate key (E11000) on mongo.WriteException/mongo.CommandError,
+// transient transaction/network/timeout classes, then E11000 in the raw
+// error string, falling
+// back to ErrTransient for anything else so an unrecognized driver failure
+// still reads as "safe to retry" rather than a hard validation error.
+func mapMongoError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var asError *Error
+	if errors.As(err, &asError) {
+		return asError
+	}
+
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return NewError(ErrNotFound, err)
+	}
+
+	var writeException mongo.WriteException
+	if errors.As(err, &writeException) {
+		for _, writeErr := range writeException.WriteErrors {
+			if writeErr.Code == 11000 || strings.Contains(writeErr.Message, "E11000") {
+				return NewError(ErrDuplicate, err)
+			}
+		}
+	}
+
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		if cmdErr.Code == 11000 || strings.Contains(cmdErr.Message, "E11000") {
+			return NewError(ErrDuplicate, err)
+		}
+		if cmdErr.HasErrorLabel("TransientTransactionError") || cmdErr.HasErrorLabel("NetworkError") {
+			return NewError(ErrTransient, err)
+		}
+	}
+
+	if mongo.IsNetworkError(err) || mongo.IsTimeout(err) {
+		return NewError(ErrTransient, err)
+	}
+
+	if strings.Contains(err.Error(), "E11000") {
+		return NewError(ErrDuplicate, err)
+	}
+
+	return NewError(ErrTransient, err)
+}
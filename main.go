@@ -19,60 +19,39 @@ import (
 )
 
 // GetStructureWebsites loads all documents from the "structure_websites" collection
-// and returns them as JSON.
+// and returns them as JSON. See GetStructureWebsitesPage for a paginated variant.
 func GetStructureWebsites(ctx context.Context) ([]map[string]interface{}, error) {
-	mongoDB, err := utils.GetMongoDB(ctx)
-	if err != nil {
-		log.Printf("Failed to connect to MongoDB: %v", err)
-		return nil, err
-	}
-
-	collection := mongoDB.Collection("structure_websites")
-	cursor, err := collection.Find(ctx, bson.M{})
+	result, err := GetStructureWebsitesPage(ctx, PageSearchOptions{})
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(ctx)
-
-	var docs []map[string]interface{}
-	for cursor.Next(ctx) {
-		var doc map[string]interface{}
-		if err := cursor.Decode(&doc); err != nil {
-			return nil, err
-		}
-		docs = append(docs, doc)
-	}
-	if err := cursor.Err(); err != nil {
-		return nil, err
-	}
-
-	return docs, nil
+	return result.List, nil
 }
 
 // UpdateStructureWebsite replaces the entire JSON document for the provided id.
 // доступно лише у режимі LOCAL_DEVELOPMENT=true, щоб уникнути випадкових змін на проді.
 func UpdateStructureWebsite(ctx context.Context, id string, payload map[string]interface{}) (map[string]interface{}, error) {
 	if strings.ToLower(os.Getenv("LOCAL_DEVELOPMENT")) != "true" {
-		return nil, fmt.Errorf("structure_websites mutation is available only when LOCAL_DEVELOPMENT=true")
+		return nil, NewError(ErrValidation, fmt.Errorf("structure_websites mutation is available only when LOCAL_DEVELOPMENT=true"))
 	}
 
 	if id == "" {
-		return nil, fmt.Errorf("id is required")
+		return nil, NewError(ErrValidation, fmt.Errorf("id is required"))
 	}
 
 	if payload == nil {
-		return nil, fmt.Errorf("payload cannot be nil")
+		return nil, NewError(ErrValidation, fmt.Errorf("payload cannot be nil"))
 	}
 
 	mongoDB, err := utils.GetMongoDB(ctx)
 	if err != nil {
 		log.Printf("Failed to connect to MongoDB: %v", err)
-		return nil, err
+		return nil, mapMongoError(err)
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid id format: %w", err)
+		return nil, NewError(ErrInvalidID, fmt.Errorf("invalid id format: %w", err))
 	}
 
 	collection := mongoDB.Collection("structure_websites")
@@ -81,7 +60,7 @@ func UpdateStructureWebsite(ctx context.Context, id string, payload map[string]i
 	err = collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&existingDoc)
 	if err != nil {
 		if err != mongo.ErrNoDocuments {
-			return nil, err
+			return nil, mapMongoError(err)
 		}
 		existingDoc = map[string]interface{}{"_id": objectID}
 	}
@@ -93,7 +72,7 @@ func UpdateStructureWebsite(ctx context.Context, id string, payload map[string]i
 	opts := options.Replace().SetUpsert(true)
 	result, err := collection.ReplaceOne(ctx, bson.M{"_id": objectID}, doc, opts)
 	if err != nil {
-		return nil, err
+		return nil, mapMongoError(err)
 	}
 
 	log.Printf(
@@ -130,20 +109,110 @@ func mergeJSONDocuments(base map[string]interface{}, updates map[string]interfac
 	return base
 }
 
+// resolveCoreDBName returns the name of the core database holding
+// structure_templates, db_clients and structure_template_snapshots, falling
+// back to "core" when MONGO_DB_NAME is unset.
+func resolveCoreDBName() string {
+	name := os.Getenv("MONGO_DB_NAME")
+	if name == "" {
+		name = "core"
+	}
+	return name
+}
+
+// RolloutMode selects how ApplyStructureTemplate handles a failure partway
+// through the per-client rollout.
+type RolloutMode string
+
+const (
+	// RolloutModeBestEffort is the original behavior: the rollout aborts on
+	// the first client error, leaving clients already processed with their
+	// writes applied and the remaining clients untouched.
+	RolloutModeBestEffort RolloutMode = "best_effort"
+	// RolloutModeAtomic wraps each client's document writes in a Mongo
+	// session transaction, so a failure partway through a client's writes
+	// rolls that client back to its pre-rollout state. The rollout still
+	// continues on to the remaining clients; per-client outcomes are
+	// collected in TemplateApplySummary.ClientResults instead of aborting
+	// the whole call.
+	RolloutModeAtomic RolloutMode = "atomic"
+)
+
+// ClientRolloutResult records the outcome of applying the template to a
+// single client database.
+type ClientRolloutResult struct {
+	ClientName       string
+	Success          bool
+	UpdatedDocuments int
+	DeletedDocuments int
+	Error            string
+}
+
+// DocumentDiff previews the effect of a template rollout on a single
+// document: Before is the client's current document (nil if it doesn't
+// exist yet), After is what sanitizeTemplateDocument/mergeTemplateValues
+// project it would become.
+type DocumentDiff struct {
+	ID     string
+	Before map[string]interface{}
+	After  map[string]interface{}
+}
+
+// ClientDiff previews the effect of a template rollout on a single client
+// database without writing anything.
+type ClientDiff struct {
+	ClientName     string
+	WouldUpdate    []DocumentDiff
+	WouldDeleteIDs []string
+}
+
 type TemplateApplySummary struct {
-	TemplateKey      string
-	TargetField      string
+	TemplateKey string
+	TargetField string
+	Mode        RolloutMode
+	DryRun      bool
+	// SnapshotID identifies the structure_template_snapshots rows written for
+	// this call (one per affected client), if any were written. Pass it to
+	// RollbackStructureTemplate to undo the rollout. Empty for dry runs.
+	SnapshotID       string
 	ClientNames      []string
+	ClientResults    []ClientRolloutResult
+	Preview          []ClientDiff
 	UpdatedDocuments int
 	DeletedDocuments int
 }
 
-func ApplyStructureTemplate(ctx context.Context, templateKey string, documents []map[string]interface{}, targetField string) (*TemplateApplySummary, error) {
+// ApplyOptions configures a single ApplyStructureTemplate call. The zero
+// value preserves the function's original behavior: targetField defaults to
+// "structure_template", Mode defaults to RolloutModeBestEffort, and DryRun
+// false performs the rollout for real.
+type ApplyOptions struct {
+	TargetField string
+	Mode        RolloutMode
+	DryRun      bool
+	// SnapshotRetention bounds how many past rollouts of the same
+	// templateKey keep a snapshot in structure_template_snapshots; each time
+	// a client's snapshot is recorded, snapshots beyond the most recent
+	// SnapshotRetention are pruned, even if a later client in the same
+	// rollout then fails. <= 0 means "keep every snapshot".
+	SnapshotRetention int
+}
+
+// ApplyStructureTemplate applies templateKey's documents to every client
+// database whose db_clients entry references it. opts.Mode decides whether a
+// failed client aborts the whole rollout (RolloutModeBestEffort, the
+// default) or is rolled back and reported while the rollout continues to
+// the remaining clients (RolloutModeAtomic). opts.DryRun skips all writes
+// and instead returns a per-client diff preview in the summary's Preview
+// field.
+func ApplyStructureTemplate(ctx context.Context, templateKey string, documents []map[string]interface{}, opts ApplyOptions) (*TemplateApplySummary, error) {
+	targetField := opts.TargetField
+	mode := opts.Mode
 	if templateKey == "" {
-		return nil, fmt.Errorf("templateKey is required")
+		return nil, NewError(ErrValidation, fmt.Errorf("templateKey is required"))
 	}
 	if len(documents) == 0 {
-		return nil, fmt.Errorf("documents payload cannot be empty")
+		return nil, NewError(ErrValidation, fmt.Errorf("documents payload cannot be empty"))
 	}
 
 	sanitizedDocs := make([]map[string]interface{}, 0, len(documents))
@@ -156,14 +225,14 @@ func ApplyStructureTemplate(ctx context.Context, templateKey string, documents [
 
 		sanitized, docID, err := prepareTemplateDocument(rawDoc)
 		if err != nil {
-			return nil, fmt.Errorf("document %d: %w", idx, err)
+			return nil, NewError(ErrInvalidID, fmt.Errorf("document %d: %w", idx, err))
 		}
 		sanitizedDocs = append(sanitizedDocs, sanitized)
 		templateIDs = append(templateIDs, docID)
 	}
 
 	if len(sanitizedDocs) == 0 {
-		return nil, fmt.Errorf("no usable documents after sanitizing input")
+		return nil, NewError(ErrValidation, fmt.Errorf("no usable documents after sanitizing input"))
 	}
 
 	if targetField == "" {
@@ -171,15 +240,10 @@ func ApplyStructureTemplate(ctx context.Context, templateKey string, documents [
 	}
 
 	if db.MongoClient == nil {
-		return nil, fmt.Errorf("mongo client is not initialized")
+		return nil, NewError(ErrTransient, fmt.Errorf("mongo client is not initialized"))
 	}
 
-	coreDBName := os.Getenv("MONGO_DB_NAME")
-	if coreDBName == "" {
-		coreDBName = "core"
-	}
-
-	coreDatabase := db.MongoClient.Database(coreDBName)
+	coreDatabase := db.MongoClient.Database(resolveCoreDBName())
 	templatesColl := coreDatabase.Collection("structure_templates")
 	clientsColl := coreDatabase.Collection("db_clients")
 
@@ -187,7 +251,7 @@ func ApplyStructureTemplate(ctx context.Context, templateKey string, documents [
 	if targetField == "structure_template" {
 		count, err := clientsColl.CountDocuments(ctx, bson.M{targetField: templateKey})
 		if err != nil {
-			return nil, fmt.Errorf("failed to count clients for template %q: %w", templateKey, err)
+			return nil, mapMongoError(fmt.Errorf("failed to count clients for template %q: %w", templateKey, err))
 		}
 		if count == 0 {
 			effectiveField = "template"
@@ -199,77 +263,248 @@ func ApplyStructureTemplate(ctx context.Context, templateKey string, documents [
 		docsForStorage[i] = cloneMap(doc)
 	}
 
-	_, err := templatesColl.UpdateOne(
-		ctx,
-		bson.M{"template_key": templateKey},
-		bson.M{"$set": bson.M{
-			"template_key": templateKey,
-			"documents":    docsForStorage,
-			"updated_at":   time.Now(),
-		}},
-		options.Update().SetUpsert(true),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to upsert template definition: %w", err)
+	if !opts.DryRun {
+		_, err := templatesColl.UpdateOne(
+			ctx,
+			bson.M{"template_key": templateKey},
+			bson.M{"$set": bson.M{
+				"template_key": templateKey,
+				"documents":    docsForStorage,
+				"updated_at":   time.Now(),
+			}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return nil, mapMongoError(fmt.Errorf("failed to upsert template definition: %w", err))
+		}
 	}
 
 	clientCursor, err := clientsColl.Find(ctx, bson.M{effectiveField: templateKey})
 	if err != nil {
-		return nil, fmt.Errorf("failed to query clients for template %q: %w", templateKey, err)
+		return nil, mapMongoError(fmt.Errorf("failed to query clients for template %q: %w", templateKey, err))
 	}
 	defer clientCursor.Close(ctx)
 
+	if mode == "" {
+		mode = RolloutModeBestEffort
+	}
+
 	summary := &TemplateApplySummary{
 		TemplateKey: templateKey,
 		TargetField: effectiveField,
+		Mode:        mode,
+		DryRun:      opts.DryRun,
 	}
 
+	var clientNames []string
 	for clientCursor.Next(ctx) {
 		var clientDoc struct {
 			ClientName string `bson:"client_name"`
 		}
 		if err := clientCursor.Decode(&clientDoc); err != nil {
-			return nil, fmt.Errorf("failed to decode client document: %w", err)
+			return nil, NewError(ErrDecode, fmt.Errorf("failed to decode client document: %w", err))
 		}
 
 		clientName := strings.TrimSpace(clientDoc.ClientName)
 		if clientName == "" {
 			continue
 		}
+		clientNames = append(clientNames, clientName)
+	}
+	if err := clientCursor.Err(); err != nil {
+		return nil, mapMongoError(fmt.Errorf("cursor error while iterating clients: %w", err))
+	}
 
-		clientDB := db.MongoClient.Database(clientName)
-		collection := clientDB.Collection("structure_websites")
-
-		for _, templateDoc := range sanitizedDocs {
-			docCopy := cloneMap(templateDoc)
-			_, err := collection.ReplaceOne(
-				ctx,
-				bson.M{"_id": docCopy["_id"]},
-				docCopy,
-				options.Replace().SetUpsert(true),
-			)
+	if opts.DryRun {
+		for _, clientName := range clientNames {
+			diff, err := previewTemplateForClient(ctx, db.MongoClient.Database(clientName), clientName, sanitizedDocs, templateIDs)
 			if err != nil {
-				return nil, fmt.Errorf("client %s: failed to upsert document: %w", clientName, err)
+				return nil, fmt.Errorf("client %s: %w", clientName, mapMongoError(err))
 			}
-			summary.UpdatedDocuments++
+			summary.Preview = append(summary.Preview, diff)
+			summary.ClientNames = append(summary.ClientNames, clientName)
 		}
+		sort.Strings(summary.ClientNames)
+		return summary, nil
+	}
 
-		deleteResult, err := collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$nin": templateIDs}})
-		if err != nil {
-			return nil, fmt.Errorf("client %s: failed to delete outdated documents: %w", clientName, err)
+	snapshotID := primitive.NewObjectID().Hex()
+
+	for _, clientName := range clientNames {
+		var (
+			updated, deleted int
+			applyErr         error
+		)
+
+		clientDB := db.MongoClient.Database(clientName)
+
+		previousDocs, prevErr := fetchClientDocuments(ctx, clientDB)
+		if prevErr != nil {
+			if mode == RolloutModeBestEffort {
+				return nil, fmt.Errorf("client %s: %w", clientName, prevErr)
+			}
+			summary.ClientResults = append(summary.ClientResults, ClientRolloutResult{
+				ClientName: clientName,
+				Success:    false,
+				Error:      prevErr.Error(),
+			})
+			continue
 		}
-		summary.DeletedDocuments += int(deleteResult.DeletedCount)
-		summary.ClientNames = append(summary.ClientNames, clientName)
-	}
 
-	if err := clientCursor.Err(); err != nil {
-		return nil, fmt.Errorf("cursor error while iterating clients: %w", err)
+		if mode == RolloutModeAtomic {
+			updated, deleted, applyErr = applyTemplateToClientAtomic(ctx, clientName, sanitizedDocs, templateIDs)
+		} else {
+			updated, deleted, applyErr = applyTemplateToClient(ctx, clientDB, sanitizedDocs, templateIDs)
+		}
+
+		if applyErr != nil {
+			if mode == RolloutModeBestEffort {
+				return nil, fmt.Errorf("client %s: %w", clientName, applyErr)
+			}
+			summary.ClientResults = append(summary.ClientResults, ClientRolloutResult{
+				ClientName: clientName,
+				Success:    false,
+				Error:      applyErr.Error(),
+			})
+			continue
+		}
+
+		if err := recordTemplateSnapshot(ctx, coreDatabase, snapshotID, templateKey, clientName, previousDocs, docsForStorage, opts.SnapshotRetention); err != nil {
+			log.Printf("[StructureWebsites] Failed to record rollback snapshot for client %s: %v", clientName, err)
+		}
+
+		summary.UpdatedDocuments += updated
+		summary.DeletedDocuments += deleted
+		summary.ClientNames = append(summary.ClientNames, clientName)
+		summary.ClientResults = append(summary.ClientResults, ClientRolloutResult{
+			ClientName:       clientName,
+			Success:          true,
+			UpdatedDocuments: updated,
+			DeletedDocuments: deleted,
+		})
 	}
 
+	summary.SnapshotID = snapshotID
 	sort.Strings(summary.ClientNames)
 	return summary, nil
 }
 
+// applyTemplateToClient upserts sanitizedDocs into clientDB's
+// structure_websites collection and deletes any existing document whose _id
+// is not in templateIDs, without a transaction.
+func applyTemplateToClient(ctx context.Context, clientDB *mongo.Database, sanitizedDocs []map[string]interface{}, templateIDs []primitive.ObjectID) (updated int, deleted int, err error) {
+	collection := clientDB.Collection("structure_websites")
+
+	for _, templateDoc := range sanitizedDocs {
+		docCopy := cloneMap(templateDoc)
+		_, err := collection.ReplaceOne(
+			ctx,
+			bson.M{"_id": docCopy["_id"]},
+			docCopy,
+			options.Replace().SetUpsert(true),
+		)
+		if err != nil {
+			return updated, deleted, mapMongoError(fmt.Errorf("failed to upsert document: %w", err))
+		}
+		updated++
+	}
+
+	deleteResult, err := collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$nin": templateIDs}})
+	if err != nil {
+		return updated, deleted, mapMongoError(fmt.Errorf("failed to delete outdated documents: %w", err))
+	}
+	deleted = int(deleteResult.DeletedCount)
+	return updated, deleted, nil
+}
+
+// applyTemplateToClientAtomic runs applyTemplateToClient's writes inside a
+// Mongo session transaction scoped to clientName's database, so a failure
+// partway through rolls that client back to its pre-rollout state instead of
+// leaving it half-updated.
+func applyTemplateToClientAtomic(ctx context.Context, clientName string, sanitizedDocs []map[string]interface{}, templateIDs []primitive.ObjectID) (updated int, deleted int, err error) {
+	session, err := db.MongoClient.StartSession()
+	if err != nil {
+		return 0, 0, mapMongoError(fmt.Errorf("failed to start session: %w", err))
+	}
+	defer session.EndSession(ctx)
+
+	result, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		clientDB := db.MongoClient.Database(clientName)
+		u, d, txErr := applyTemplateToClient(sessCtx, clientDB, sanitizedDocs, templateIDs)
+		if txErr != nil {
+			return nil, txErr
+		}
+		return [2]int{u, d}, nil
+	})
+	if err != nil {
+		return 0, 0, mapMongoError(err)
+	}
+
+	counts := result.([2]int)
+	return counts[0], counts[1], nil
+}
+
+// previewTemplateForClient computes what applyTemplateToClient would do to
+// clientDB without writing anything: for each template document it merges
+// the sanitized template shape onto the client's existing document (if any)
+// via mergeTemplateValues, and separately lists the ids that would be
+// deleted for no longer being part of the template.
+func previewTemplateForClient(ctx context.Context, clientDB *mongo.Database, clientName string, sanitizedDocs []map[string]interface{}, templateIDs []primitive.ObjectID) (ClientDiff, error) {
+	collection := clientDB.Collection("structure_websites")
+
+	cursor, err := collection.Find(ctx, bson.M{})
+	if err != nil {
+		return ClientDiff{}, mapMongoError(fmt.Errorf("failed to query existing documents: %w", err))
+	}
+	defer cursor.Close(ctx)
+
+	existingByID := make(map[primitive.ObjectID]map[string]interface{})
+	for cursor.Next(ctx) {
+		var doc map[string]interface{}
+		if err := cursor.Decode(&doc); err != nil {
+			return ClientDiff{}, NewError(ErrDecode, fmt.Errorf("failed to decode existing document: %w", err))
+		}
+		if id, ok := doc["_id"].(primitive.ObjectID); ok {
+			existingByID[id] = doc
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return ClientDiff{}, mapMongoError(fmt.Errorf("cursor error while reading existing documents: %w", err))
+	}
+
+	templateIDSet := make(map[primitive.ObjectID]struct{}, len(templateIDs))
+	for _, id := range templateIDs {
+		templateIDSet[id] = struct{}{}
+	}
+
+	diff := ClientDiff{ClientName: clientName}
+	for _, templateDoc := range sanitizedDocs {
+		id, _ := templateDoc["_id"].(primitive.ObjectID)
+
+		var before map[string]interface{}
+		base := make(map[string]interface{})
+		if existing, ok := existingByID[id]; ok {
+			before = cloneMap(existing)
+			base = cloneMap(existing)
+		}
+
+		after, _ := mergeTemplateValues(base, cloneMap(templateDoc)).(map[string]interface{})
+		diff.WouldUpdate = append(diff.WouldUpdate, DocumentDiff{
+			ID:     id.Hex(),
+			Before: before,
+			After:  after,
+		})
+	}
+
+	for id := range existingByID {
+		if _, ok := templateIDSet[id]; !ok {
+			diff.WouldDeleteIDs = append(diff.WouldDeleteIDs, id.Hex())
+		}
+	}
+
+	return diff, nil
+}
+
 func prepareTemplateDocument(doc map[string]interface{}) (map[string]interface{}, primitive.ObjectID, error) {
 	sanitized := sanitizeTemplateDocument(doc)
 
@@ -0,0 +1,31 @@
+package structure_websites
+
+import (
+	"context"
+	"log"
+
+	"w100n_backend_core/core/db/utils"
+
+	"web100now-clients-platform/app/plugins/w100n_structure_websites/sub_plugins/pagination"
+)
+
+// PageSearchOptions and PageResult are aliases for the shared pagination
+// package's types, kept under their historical names here so callers in this
+// package don't need to change.
+type (
+	PageSearchOptions = pagination.SearchOptions
+	PageResult        = pagination.Result
+)
+
+// GetStructureWebsitesPage loads documents from "structure_websites" with
+// optional pagination, projection and sorting. GetStructureWebsites calls
+// this with a zero-value PageSearchOptions to keep its existing behavior.
+func GetStructureWebsitesPage(ctx context.Context, opts PageSearchOptions) (*PageResult, error) {
+	mongoDB, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		log.Printf("Failed to connect to MongoDB: %v", err)
+		return nil, err
+	}
+
+	return pagination.FetchPage(ctx, mongoDB, "structure_websites", opts)
+}
@@ -2,15 +2,16 @@ package structure_websites
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
+	"go.mongodb.org/mongo-driver/bson"
+
 	"web100now-clients-platform/app/graph/model"
 	"web100now-clients-platform/core"
 	"web100now-clients-platform/core/db/utils"
 	"web100now-clients-platform/core/logger"
-
-	"go.mongodb.org/mongo-driver/bson"
 )
 
 // Resolver is the resolver for the structure_websites plugin.
@@ -26,42 +27,85 @@ func NewResolver() *Resolver {
 func (r *Resolver) StructureWebsites(ctx context.Context) ([]core.JSON, error) {
 	logger.LogInfo("[StructureWebsites] StructureWebsites called - Fetching all documents")
 
-	mongoDB, err := utils.GetMongoDB(ctx)
+	result, err := r.structureWebsitesPage(ctx, PageSearchOptions{})
 	if err != nil {
-		logger.LogError("[StructureWebsites] Failed to connect to MongoDB", err)
+		logger.LogError("[StructureWebsites] Failed to load structure_websites collection", err)
 		return nil, err
 	}
 
-	logger.LogInfo("[StructureWebsites] MongoDB connection established successfully")
+	logger.LogInfo(fmt.Sprintf("[StructureWebsites] Successfully loaded %d document(s) from structure_websites", len(result.List)))
 
-	collection := mongoDB.Collection("structure_websites")
-	cursor, err := collection.Find(ctx, bson.M{})
-	if err != nil {
-		logger.LogError("[StructureWebsites] Failed to query structure_websites collection", err)
+	return toJSONList(result.List), nil
+}
+
+// structureWebsitesPage is the pagination-aware implementation backing
+// StructureWebsites. It is not yet exposed as its own GraphQL field -
+// wiring it up needs a matching field/type added to the platform's GraphQL
+// schema - but the paginated fetch is ready for that field to call into.
+func (r *Resolver) structureWebsitesPage(ctx context.Context, opts PageSearchOptions) (*PageResult, error) {
+	if _, err := utils.GetMongoDB(ctx); err != nil {
+		logger.LogError("[StructureWebsites] Failed to connect to MongoDB", err)
 		return nil, err
 	}
-	defer cursor.Close(ctx)
-
-	var result []core.JSON
-	docCount := 0
-	for cursor.Next(ctx) {
-		var doc map[string]interface{}
-		if err := cursor.Decode(&doc); err != nil {
-			logger.LogError("[StructureWebsites] Failed to decode document", err)
-			continue
-		}
-		result = append(result, core.JSON(doc))
-		docCount++
+
+	logger.LogInfo("[StructureWebsites] MongoDB connection established successfully")
+
+	return GetStructureWebsitesPage(ctx, opts)
+}
+
+// logStructureWebsitesError logs err, including its Error.Code when err is
+// (or wraps) one, so categorized failures show up next to opaque driver
+// errors instead of requiring callers to parse err.Error() for the code.
+func logStructureWebsitesError(label string, err error) {
+	var structuredErr *Error
+	if errors.As(err, &structuredErr) {
+		logger.LogError(fmt.Sprintf("%s [%s]", label, structuredErr.Code), err)
+		return
 	}
+	logger.LogError(label, err)
+}
 
-	if err := cursor.Err(); err != nil {
-		logger.LogError("[StructureWebsites] Cursor iteration error", err)
-		return nil, err
+func toJSONList(docs []map[string]interface{}) []core.JSON {
+	result := make([]core.JSON, 0, len(docs))
+	for _, doc := range docs {
+		result = append(result, core.JSON(doc))
 	}
+	return result
+}
 
-	logger.LogInfo(fmt.Sprintf("[StructureWebsites] Successfully loaded %d document(s) from structure_websites", docCount))
+// StructureWebsitesStream loads documents from "structure_websites" matching
+// filter incrementally: each decoded document is pushed onto the returned
+// channel as the underlying cursor advances, instead of materializing the
+// whole result set the way StructureWebsites does. This mirrors the
+// streaming loader pattern in mongox-go-driver and keeps peak memory bounded
+// for large tenants.
+//
+// The document channel is always closed once streaming ends. The error
+// channel receives at most one error (the cause of the streaming failure, if
+// any) and is then closed.
+func (r *Resolver) StructureWebsitesStream(ctx context.Context, filter bson.M) (<-chan core.JSON, <-chan error) {
+	docs := make(chan core.JSON)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(docs)
+		defer close(errs)
+
+		err := StreamStructureWebsites(ctx, filter, func(doc map[string]interface{}) error {
+			select {
+			case docs <- core.JSON(doc):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			logger.LogError("[StructureWebsites] StructureWebsitesStream failed", err)
+			errs <- err
+		}
+	}()
 
-	return result, nil
+	return docs, errs
 }
 
 // UpdateStructureWebsite дозволяє повністю перезаписати документ (лише у LOCAL_DEVELOPMENT режимі).
@@ -70,7 +114,7 @@ func (r *Resolver) UpdateStructureWebsite(ctx context.Context, id string, payloa
 
 	updatedDoc, err := UpdateStructureWebsite(ctx, id, map[string]interface{}(payload))
 	if err != nil {
-		logger.LogError(fmt.Sprintf("[StructureWebsites] Failed to update document %s", id), err)
+		logStructureWebsitesError(fmt.Sprintf("[StructureWebsites] Failed to update document %s", id), err)
 		return nil, err
 	}
 
@@ -95,9 +139,15 @@ func (r *Resolver) ApplyStructureTemplate(ctx context.Context, input model.Struc
 		targetField = strings.TrimSpace(*input.TargetField)
 	}
 
-	summary, err := ApplyStructureTemplate(ctx, input.TemplateKey, documents, targetField)
+	// RolloutModeBestEffort and DryRun=false preserve the existing
+	// GraphQL-visible behavior; once StructureTemplateInput exposes rollout
+	// mode/dry-run fields, thread them through here instead of hard-coding.
+	summary, err := ApplyStructureTemplate(ctx, input.TemplateKey, documents, ApplyOptions{
+		TargetField: targetField,
+		Mode:        RolloutModeBestEffort,
+	})
 	if err != nil {
-		logger.LogError("[StructureWebsites] ApplyStructureTemplate failed", err)
+		logStructureWebsitesError("[StructureWebsites] ApplyStructureTemplate failed", err)
 		return nil, err
 	}
 
@@ -121,3 +171,67 @@ func (r *Resolver) ApplyStructureTemplate(ctx context.Context, input model.Struc
 		Message:          message,
 	}, nil
 }
+
+// RollbackStructureTemplate restores every client recorded under snapshotID
+// to its pre-rollout structure_websites state. It is not yet wired to a
+// GraphQL mutation field - StructureTemplateInput/ApplyStructureTemplateResult
+// have no snapshot id to thread through yet - but the rollback itself is
+// ready for that field to call into.
+func (r *Resolver) RollbackStructureTemplate(ctx context.Context, snapshotID string) (*model.ApplyStructureTemplateResult, error) {
+	logger.LogInfo(fmt.Sprintf("[StructureWebsites] RollbackStructureTemplate called - Snapshot: %s", snapshotID))
+
+	summary, err := RollbackStructureTemplate(ctx, snapshotID)
+	if err != nil {
+		logStructureWebsitesError("[StructureWebsites] RollbackStructureTemplate failed", err)
+		return nil, err
+	}
+
+	message := fmt.Sprintf(
+		"Template %s rolled back from snapshot %s for %d client(s); restored %d document(s), removed %d document(s)",
+		summary.TemplateKey,
+		snapshotID,
+		len(summary.ClientNames),
+		summary.UpdatedDocuments,
+		summary.DeletedDocuments,
+	)
+
+	logger.LogInfo(fmt.Sprintf("[StructureWebsites] %s", message))
+
+	return &model.ApplyStructureTemplateResult{
+		TemplateKey:      summary.TemplateKey,
+		AffectedClients:  summary.ClientNames,
+		UpdatedDocuments: summary.UpdatedDocuments,
+		DeletedDocuments: summary.DeletedDocuments,
+		Message:          message,
+	}, nil
+}
+
+// ForkStructureTemplate clones sourceTemplateKey's stored documents under
+// newTemplateKey without touching any client database. It is not yet wired
+// to a GraphQL mutation field - that needs a matching field/input added to
+// the platform's GraphQL schema - but the fork itself is ready for that
+// field to call into.
+func (r *Resolver) ForkStructureTemplate(ctx context.Context, sourceTemplateKey string, newTemplateKey string) (*model.ApplyStructureTemplateResult, error) {
+	logger.LogInfo(fmt.Sprintf("[StructureWebsites] ForkStructureTemplate called - Source: %s, New: %s", sourceTemplateKey, newTemplateKey))
+
+	summary, err := ForkStructureTemplate(ctx, sourceTemplateKey, newTemplateKey)
+	if err != nil {
+		logStructureWebsitesError("[StructureWebsites] ForkStructureTemplate failed", err)
+		return nil, err
+	}
+
+	message := fmt.Sprintf(
+		"Template %s forked from %s with %d document(s)",
+		summary.TemplateKey,
+		sourceTemplateKey,
+		summary.UpdatedDocuments,
+	)
+
+	logger.LogInfo(fmt.Sprintf("[StructureWebsites] %s", message))
+
+	return &model.ApplyStructureTemplateResult{
+		TemplateKey:      summary.TemplateKey,
+		UpdatedDocuments: summary.UpdatedDocuments,
+		Message:          message,
+	}, nil
+}
@@ -0,0 +1,45 @@
+package structure_websites
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"w100n_backend_core/core/db/utils"
+)
+
+// StreamStructureWebsites iterates the "structure_websites" collection
+// matching filter (nil means no filter) and invokes fn once per document
+// while the underlying Mongo cursor stays open, instead of materializing the
+// whole result set in memory the way GetStructureWebsites does. Iteration
+// stops as soon as fn returns a non-nil error or the cursor itself fails;
+// that error is returned to the caller.
+func StreamStructureWebsites(ctx context.Context, filter bson.M, fn func(doc map[string]interface{}) error) error {
+	mongoDB, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		return err
+	}
+
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	collection := mongoDB.Collection("structure_websites")
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc map[string]interface{}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
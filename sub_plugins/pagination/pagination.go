@@ -0,0 +1,92 @@
+// Package pagination provides the paginated-Mongo-read types and helper
+// shared by every sub_plugin's collection-page fetchers, so each package
+// doesn't redefine the same SearchOptions/Result shape and FetchPage body.
+package pagination
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SearchOptions configures a paginated, projected, sorted read of a single
+// collection. A zero-value SearchOptions preserves the previous unpaginated,
+// unfiltered, unsorted behavior: Query/Project/Sort default to "no filter"
+// and Size <= 0 means "no limit".
+type SearchOptions struct {
+	Page    int
+	Size    int
+	Query   bson.M
+	Project bson.M
+	Sort    bson.M
+}
+
+// Result is the paginated response envelope returned alongside a page of
+// documents, so callers can build pagination UIs on top without a second
+// round-trip.
+type Result struct {
+	List  []map[string]interface{}
+	Total int64
+	Page  int
+	Size  int
+}
+
+// FetchPage reads a single page of documents from collectionName, honoring
+// opts.Query/Project/Sort/Page/Size, and reports Total via CountDocuments
+// against the same filter.
+func FetchPage(ctx context.Context, db *mongo.Database, collectionName string, opts SearchOptions) (*Result, error) {
+	collection := db.Collection(collectionName)
+
+	filter := opts.Query
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	findOpts := options.Find()
+	if opts.Project != nil {
+		findOpts.SetProjection(opts.Project)
+	}
+	if opts.Sort != nil {
+		findOpts.SetSort(opts.Sort)
+	}
+	if opts.Size > 0 {
+		page := opts.Page
+		if page < 1 {
+			page = 1
+		}
+		findOpts.SetLimit(int64(opts.Size))
+		findOpts.SetSkip(int64((page - 1) * opts.Size))
+	}
+
+	cursor, err := collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var documents []map[string]interface{}
+	for cursor.Next(ctx) {
+		var doc map[string]interface{}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		documents = append(documents, doc)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		List:  documents,
+		Total: total,
+		Page:  opts.Page,
+		Size:  opts.Size,
+	}, nil
+}
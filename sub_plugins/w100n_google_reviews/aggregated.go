@@ -0,0 +1,122 @@
+package google_reviews
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"web100now-clients-platform/app/graph/model"
+	"web100now-clients-platform/app/plugins/w100n_structure_websites/sub_plugins/w100n_google_reviews/providers"
+	"web100now-clients-platform/app/plugins/w100n_structure_websites/sub_plugins/w100n_google_reviews/reviewcache"
+	"web100now-clients-platform/core/db/utils"
+	"web100now-clients-platform/core/logger"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ReviewSource identifies which ReviewProvider a review was aggregated from.
+// This mirrors the ReviewSource GraphQL enum AggregatedReviews is written
+// against, ready for the day that enum is added to app/graph/model.
+type ReviewSource string
+
+const (
+	ReviewSourceGoogle      ReviewSource = "google"
+	ReviewSourceTripAdvisor ReviewSource = "tripadvisor"
+	ReviewSourceYelp        ReviewSource = "yelp"
+)
+
+// aggregatedReviewCache is the process-wide reviewcache.Cache shared by every
+// AggregatedReviews call, the same way db.MongoClient is a single shared
+// connection rather than one per request.
+var aggregatedReviewCache = reviewcache.NewCache(5 * time.Minute)
+
+// providerFor constructs the ReviewProvider for source, or nil if source is
+// unrecognized. Google's provider needs db; TripAdvisor/Yelp read their API
+// keys from the same env-var convention sync.PlacesClient's key does.
+func providerFor(source ReviewSource, db *mongo.Database) providers.ReviewProvider {
+	switch source {
+	case ReviewSourceGoogle:
+		return providers.NewGoogleProvider(db)
+	case ReviewSourceTripAdvisor:
+		return providers.NewTripAdvisorProvider(os.Getenv("TRIPADVISOR_API_KEY"))
+	case ReviewSourceYelp:
+		return providers.NewYelpProvider(os.Getenv("YELP_API_KEY"))
+	default:
+		return nil
+	}
+}
+
+// AggregatedReviews fetches placeID's reviews from each of sources (through
+// their ReviewProvider, transparently caching each source's result in
+// aggregatedReviewCache for its TTL), merges them, de-duplicates by
+// (source, id_review), and ranks the result by rating (desc) then
+// retrieval date (desc, most recent first).
+func (r *Resolver) AggregatedReviews(ctx context.Context, placeID string, sources []ReviewSource) ([]*model.Review, error) {
+	db, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		logger.LogError("[AggregatedReviews] Failed to connect to database", err)
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	if len(sources) == 0 {
+		sources = []ReviewSource{ReviewSourceGoogle, ReviewSourceTripAdvisor, ReviewSourceYelp}
+	}
+
+	seen := make(map[string]bool)
+	var merged []providers.Review
+
+	for _, source := range sources {
+		provider := providerFor(source, db)
+		if provider == nil {
+			logger.LogError("[AggregatedReviews] Unknown review source", fmt.Errorf("source %q", source))
+			continue
+		}
+
+		cacheKey := reviewcache.Key{Source: provider.Name(), PlaceRef: placeID}
+		reviews, cached := aggregatedReviewCache.Get(cacheKey)
+		if !cached {
+			reviews, err = provider.Fetch(ctx, providers.PlaceRef{ID: placeID})
+			if err != nil {
+				logger.LogError(fmt.Sprintf("[AggregatedReviews] Failed to fetch reviews from %s", provider.Name()), err)
+				continue
+			}
+			aggregatedReviewCache.Set(cacheKey, reviews)
+		}
+
+		for _, review := range reviews {
+			dedupeKey := review.Source + "|" + review.IDReview
+			if seen[dedupeKey] {
+				continue
+			}
+			seen[dedupeKey] = true
+			merged = append(merged, review)
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].Rating != merged[j].Rating {
+			return merged[i].Rating > merged[j].Rating
+		}
+		return merged[i].RetrievalDate > merged[j].RetrievalDate
+	})
+
+	result := make([]*model.Review, 0, len(merged))
+	for _, review := range merged {
+		result = append(result, &model.Review{
+			AuthorName:              review.AuthorName,
+			Rating:                  review.Rating,
+			Text:                    review.Text,
+			RelativeTimeDescription: review.RelativeTimeDescription,
+			RetrievalDate:           review.RetrievalDate,
+			Status:                  review.Status,
+			IDReview:                review.IDReview,
+			NReviewUser:             review.NReviewUser,
+			NPhotoUser:              review.NPhotoUser,
+			URLUser:                 review.URLUser,
+			Source:                  review.Source,
+		})
+	}
+	return result, nil
+}
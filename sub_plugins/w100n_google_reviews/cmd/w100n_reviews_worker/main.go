@@ -0,0 +1,58 @@
+// Command w100n_reviews_worker runs the background sync that keeps the
+// google_reviews collection fresh from the Google Places Details API. See
+// the sync package for the job-queue and worker-pool implementation.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"web100now-clients-platform/app/plugins/w100n_structure_websites/sub_plugins/w100n_google_reviews/sync"
+	"web100now-clients-platform/core/db"
+	"web100now-clients-platform/core/db/utils"
+)
+
+func main() {
+	apiKey := os.Getenv("GOOGLE_PLACES_API_KEY")
+	if apiKey == "" {
+		log.Fatal("GOOGLE_PLACES_API_KEY is required")
+	}
+
+	dbName := os.Getenv("MONGO_DB_NAME")
+	if dbName == "" {
+		dbName = "core"
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// utils.GetMongoDB establishes (and caches) the shared Mongo connection,
+	// the same way every other entrypoint in this repo bootstraps it; the
+	// worker then reaches clients through db.MongoClient directly, as
+	// ApplyStructureTemplate already does.
+	if _, err := utils.GetMongoDB(ctx); err != nil {
+		log.Fatalf("failed to connect to MongoDB: %v", err)
+	}
+	if db.MongoClient == nil {
+		log.Fatal("mongo client is not initialized")
+	}
+
+	reviewsDB := db.MongoClient.Database(dbName)
+	queue := sync.NewQueue(reviewsDB)
+	client := sync.NewPlacesClient(apiKey)
+	worker := sync.NewWorker(queue, client, reviewsDB)
+
+	if poolSize := os.Getenv("REVIEWS_WORKER_POOL_SIZE"); poolSize != "" {
+		if n, err := strconv.Atoi(poolSize); err == nil && n > 0 {
+			worker.PoolSize = n
+		}
+	}
+
+	log.Printf("[ReviewsWorker] Starting with pool size %d, poll interval %s", worker.PoolSize, worker.PollInterval)
+	worker.Run(ctx)
+	log.Println("[ReviewsWorker] Stopped")
+}
@@ -3,69 +3,335 @@ package functions
 import (
 	"context"
 	"fmt"
+	"regexp"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-// FetchGoogleReviewsJSON зчитує документи з колекції google_reviews,
-// відфільтровує відгуки з status == true та не показує ті, у яких поле "text" пусте,
-// і повертає результат у вигляді map.
-func FetchGoogleReviewsJSON(ctx context.Context, db *mongo.Database) (map[string][]map[string]interface{}, error) {
-	collections := []string{
-		"google_reviews",
+// FlexibleObjectID decodes a BSON value that is either an ObjectID or a
+// legacy hex string into a primitive.ObjectID, so google_reviews documents
+// written before this collection settled on ObjectID _id values still
+// decode through cursor.All instead of failing outright.
+type FlexibleObjectID primitive.ObjectID
+
+// UnmarshalBSONValue implements bson.ValueUnmarshaler.
+func (id *FlexibleObjectID) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	switch t {
+	case bsontype.ObjectID:
+		var oid primitive.ObjectID
+		if err := bson.UnmarshalValue(t, data, &oid); err != nil {
+			return fmt.Errorf("failed to decode _id as ObjectID: %w", err)
+		}
+		*id = FlexibleObjectID(oid)
+		return nil
+	case bsontype.String:
+		var s string
+		if err := bson.UnmarshalValue(t, data, &s); err != nil {
+			return fmt.Errorf("failed to decode _id as string: %w", err)
+		}
+		oid, err := primitive.ObjectIDFromHex(s)
+		if err != nil {
+			return fmt.Errorf("legacy _id %q is not a valid ObjectID hex string: %w", s, err)
+		}
+		*id = FlexibleObjectID(oid)
+		return nil
+	default:
+		return fmt.Errorf("cannot decode _id of BSON type %s into an ObjectID", t)
 	}
+}
 
-	results := make(map[string][]map[string]interface{})
+// Hex returns id's hex string representation, same as primitive.ObjectID.Hex.
+func (id FlexibleObjectID) Hex() string {
+	return primitive.ObjectID(id).Hex()
+}
 
-	for _, coll := range collections {
-		docs, err := fetchAllDocuments(ctx, db, coll)
-		if err != nil {
-			// Якщо документів немає – записуємо порожній зріз
-			if err == mongo.ErrNoDocuments {
-				results[coll] = []map[string]interface{}{}
-			} else {
-				return nil, fmt.Errorf("failed to fetch %s: %w", coll, err)
-			}
-		} else {
-			// Для колекції google_reviews – фільтруємо відгуки з status == true
-			// і не включаємо ті, у яких поле "text" пусте.
-			if coll == "google_reviews" {
-				for i, doc := range docs {
-					// Обробляємо як primitive.A, так і []interface{}
-					if reviews, ok := doc["reviews"].(primitive.A); ok {
-						var filteredReviews []interface{}
-						for _, review := range reviews {
-							if reviewMap, ok := review.(map[string]interface{}); ok {
-								if status, ok := reviewMap["status"].(bool); ok && status {
-									if text, ok := reviewMap["text"].(string); ok && text != "" {
-										filteredReviews = append(filteredReviews, reviewMap)
-									}
-								}
-							}
-						}
-						docs[i]["reviews"] = filteredReviews
-					} else if reviews, ok := doc["reviews"].([]interface{}); ok {
-						var filteredReviews []interface{}
-						for _, review := range reviews {
-							if reviewMap, ok := review.(map[string]interface{}); ok {
-								if status, ok := reviewMap["status"].(bool); ok && status {
-									if text, ok := reviewMap["text"].(string); ok && text != "" {
-										filteredReviews = append(filteredReviews, reviewMap)
-									}
-								}
-							}
-						}
-						docs[i]["reviews"] = filteredReviews
-					}
-				}
-			}
-			results[coll] = docs
+// FlexibleInt decodes a BSON int32, int64 or double into an int. Ratings in
+// this collection have been written as any of those three numeric BSON
+// types over the years; FlexibleInt accepts all of them instead of silently
+// zeroing out on a mismatch.
+type FlexibleInt int
+
+// UnmarshalBSONValue implements bson.ValueUnmarshaler.
+func (n *FlexibleInt) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	switch t {
+	case bsontype.Int32:
+		var v int32
+		if err := bson.UnmarshalValue(t, data, &v); err != nil {
+			return err
+		}
+		*n = FlexibleInt(v)
+	case bsontype.Int64:
+		var v int64
+		if err := bson.UnmarshalValue(t, data, &v); err != nil {
+			return err
+		}
+		*n = FlexibleInt(v)
+	case bsontype.Double:
+		var v float64
+		if err := bson.UnmarshalValue(t, data, &v); err != nil {
+			return err
+		}
+		*n = FlexibleInt(v)
+	default:
+		return fmt.Errorf("cannot decode rating of BSON type %s into an int", t)
+	}
+	return nil
+}
+
+// ReviewDoc is one entry in a GoogleReviewDoc's Reviews slice, decoded
+// directly from BSON via cursor.All rather than through
+// map[string]interface{} type-switching.
+type ReviewDoc struct {
+	AuthorName              string      `bson:"author_name"`
+	Rating                  FlexibleInt `bson:"rating"`
+	Text                    string      `bson:"text"`
+	RelativeTimeDescription string      `bson:"relative_time_description"`
+	RetrievalDate           string      `bson:"retrieval_date"`
+	Status                  bool        `bson:"status"`
+	IDReview                string      `bson:"id_review"`
+	NReviewUser             string      `bson:"n_review_user"`
+	NPhotoUser              string      `bson:"n_photo_user"`
+	URLUser                 string      `bson:"url_user"`
+	ModerationReason        string      `bson:"moderation_reason"`
+	Source                  string      `bson:"source"`
+}
+
+// GoogleReviewDoc is a full google_reviews document, decoded directly by
+// cursor.All instead of into map[string]interface{}.
+type GoogleReviewDoc struct {
+	ID      FlexibleObjectID `bson:"_id"`
+	Name    string           `bson:"name"`
+	Rating  FlexibleInt      `bson:"rating"`
+	Reviews []ReviewDoc      `bson:"reviews"`
+}
+
+// FetchGoogleReviewsDocs decodes every google_reviews document directly into
+// GoogleReviewDoc via cursor.All and filters each document's Reviews down to
+// status == true entries with a non-empty Text.
+func FetchGoogleReviewsDocs(ctx context.Context, db *mongo.Database) ([]GoogleReviewDoc, error) {
+	collection := db.Collection("google_reviews")
+
+	cursor, err := collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []GoogleReviewDoc
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode google_reviews documents: %w", err)
+	}
+
+	for i := range docs {
+		docs[i].Reviews = filterReviewDocs(docs[i].Reviews)
+	}
+
+	return docs, nil
+}
+
+// filterReviewDocs returns the subset of reviews with status == true and a
+// non-empty Text.
+func filterReviewDocs(reviews []ReviewDoc) []ReviewDoc {
+	filtered := make([]ReviewDoc, 0, len(reviews))
+	for _, r := range reviews {
+		if r.Status && r.Text != "" {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// FetchGoogleReviewsJSON decodes every document in the google_reviews
+// collection directly into GoogleReviewDoc via FetchGoogleReviewsDocs. It
+// used to return map[string][]map[string]interface{} built from manual
+// per-field type-switching (three branches per integer to cope with
+// int32/int/float64); that was fragile (a BSON type mismatch silently
+// produced a zero field) and allocation-heavy on large result sets.
+func FetchGoogleReviewsJSON(ctx context.Context, db *mongo.Database) ([]GoogleReviewDoc, error) {
+	docs, err := FetchGoogleReviewsDocs(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch google_reviews: %w", err)
+	}
+	return docs, nil
+}
+
+// ReviewSortField selects which unwound review field FetchGoogleReviewsConnection
+// orders by.
+type ReviewSortField string
+
+const (
+	ReviewSortByRetrievalDate ReviewSortField = "retrieval_date"
+	ReviewSortByRating        ReviewSortField = "rating"
+	ReviewSortByAuthor        ReviewSortField = "author_name"
+)
+
+// ReviewFilter narrows which unwound reviews FetchGoogleReviewsConnection
+// returns. A zero-value field means "no constraint on that field"; DateFrom
+// and DateTo are RFC3339 strings compared lexicographically, matching the
+// existing retrieval_date field's stored type.
+type ReviewFilter struct {
+	MinRating    int
+	TextContains string
+	Author       string
+	DateFrom     string
+	DateTo       string
+	Status       *bool
+}
+
+// ReviewSort orders the unwound reviews aggregation by Field (defaulting to
+// ReviewSortByRetrievalDate), descending when Descending is set.
+type ReviewSort struct {
+	Field      ReviewSortField
+	Descending bool
+}
+
+// ReviewCursor identifies a position in a retrieval_date-ordered review
+// list, as {retrieval_date, id_review}. It is the unit the GraphQL resolver
+// base64-encodes into GoogleReviewsConnection's opaque edge cursors.
+type ReviewCursor struct {
+	RetrievalDate string
+	IDReview      string
+}
+
+// ReviewConnectionPage is one page of individually unwound reviews (not
+// place documents - each map is a single entry from a google_reviews
+// document's "reviews" array), along with the total count matching Filter
+// and whether a further page exists beyond it.
+type ReviewConnectionPage struct {
+	Reviews     []map[string]interface{}
+	TotalCount  int64
+	HasNextPage bool
+}
+
+// FetchGoogleReviewsConnection runs a MongoDB aggregation over the
+// google_reviews collection that $unwinds each document's reviews array into
+// individual rows, $matches filter, $sorts by sort, and then, for the page
+// data only, keeps rows positioned after `after` (if set) and $limits to the
+// first `first` rows (plus one extra, to detect HasNextPage). TotalCount is
+// counted over every row matching filter, regardless of after.
+//
+// after is always compared against {retrieval_date, id_review} per
+// ReviewCursor's fixed shape: this keeps cursor pagination correct when
+// sort.Field is the default ReviewSortByRetrievalDate, and is a best-effort
+// position marker (ordering still honors sort.Field, but "after" is
+// evaluated via the retrieval_date/id_review tuple) when a different
+// sort.Field is chosen.
+func FetchGoogleReviewsConnection(ctx context.Context, db *mongo.Database, filter ReviewFilter, sort ReviewSort, first int, after *ReviewCursor) (*ReviewConnectionPage, error) {
+	collection := db.Collection("google_reviews")
+
+	match := bson.M{}
+	if filter.Status != nil {
+		match["reviews.status"] = *filter.Status
+	}
+	if filter.MinRating > 0 {
+		match["reviews.rating"] = bson.M{"$gte": filter.MinRating}
+	}
+	if filter.TextContains != "" {
+		match["reviews.text"] = bson.M{"$regex": regexp.QuoteMeta(filter.TextContains), "$options": "i"}
+	}
+	if filter.Author != "" {
+		match["reviews.author_name"] = bson.M{"$regex": regexp.QuoteMeta(filter.Author), "$options": "i"}
+	}
+	if filter.DateFrom != "" || filter.DateTo != "" {
+		dateRange := bson.M{}
+		if filter.DateFrom != "" {
+			dateRange["$gte"] = filter.DateFrom
+		}
+		if filter.DateTo != "" {
+			dateRange["$lte"] = filter.DateTo
+		}
+		match["reviews.retrieval_date"] = dateRange
+	}
+
+	sortField := sort.Field
+	if sortField == "" {
+		sortField = ReviewSortByRetrievalDate
+	}
+	sortDir := 1
+	if sort.Descending {
+		sortDir = -1
+	}
+
+	dataStages := bson.A{}
+	if after != nil {
+		cursorOp := "$gt"
+		if sort.Descending {
+			cursorOp = "$lt"
+		}
+		dataStages = append(dataStages, bson.M{"$match": bson.M{"$or": bson.A{
+			bson.M{"reviews.retrieval_date": bson.M{cursorOp: after.RetrievalDate}},
+			bson.M{
+				"reviews.retrieval_date": after.RetrievalDate,
+				"reviews.id_review":      bson.M{cursorOp: after.IDReview},
+			},
+		}}})
+	}
+
+	if first <= 0 {
+		first = 20
+	}
+	dataStages = append(dataStages, bson.M{"$limit": int64(first + 1)})
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$unwind", Value: "$reviews"}},
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "reviews." + string(sortField), Value: sortDir},
+			{Key: "reviews.retrieval_date", Value: sortDir},
+			{Key: "reviews.id_review", Value: sortDir},
+		}}},
+		{{Key: "$facet", Value: bson.M{
+			"data":       dataStages,
+			"totalCount": bson.A{bson.M{"$count": "count"}},
+		}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate google_reviews connection: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var facetResult struct {
+		Data       []map[string]interface{} `bson:"data"`
+		TotalCount []struct {
+			Count int64 `bson:"count"`
+		} `bson:"totalCount"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&facetResult); err != nil {
+			return nil, fmt.Errorf("failed to decode google_reviews connection facet: %w", err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error while aggregating google_reviews connection: %w", err)
+	}
+
+	var total int64
+	if len(facetResult.TotalCount) > 0 {
+		total = facetResult.TotalCount[0].Count
+	}
+
+	hasNextPage := len(facetResult.Data) > first
+	if hasNextPage {
+		facetResult.Data = facetResult.Data[:first]
+	}
+
+	reviews := make([]map[string]interface{}, 0, len(facetResult.Data))
+	for _, doc := range facetResult.Data {
+		if reviewDoc, ok := doc["reviews"].(map[string]interface{}); ok {
+			reviews = append(reviews, reviewDoc)
 		}
 	}
 
-	return results, nil
+	return &ReviewConnectionPage{
+		Reviews:     reviews,
+		TotalCount:  total,
+		HasNextPage: hasNextPage,
+	}, nil
 }
 
 // fetchAllDocuments зчитує всі документи з вказаної колекції.
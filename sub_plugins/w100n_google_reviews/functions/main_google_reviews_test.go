@@ -0,0 +1,106 @@
+package functions
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFlexibleObjectID_UnmarshalBSONValue(t *testing.T) {
+	oid := primitive.NewObjectID()
+
+	tests := []struct {
+		name    string
+		doc     bson.M
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "native ObjectID",
+			doc:  bson.M{"_id": oid},
+			want: oid.Hex(),
+		},
+		{
+			name: "legacy hex string",
+			doc:  bson.M{"_id": oid.Hex()},
+			want: oid.Hex(),
+		},
+		{
+			name:    "malformed legacy string",
+			doc:     bson.M{"_id": "not-a-valid-hex-id"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := bson.Marshal(tt.doc)
+			if err != nil {
+				t.Fatalf("bson.Marshal: %v", err)
+			}
+
+			var decoded struct {
+				ID FlexibleObjectID `bson:"_id"`
+			}
+			err = bson.Unmarshal(raw, &decoded)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("bson.Unmarshal: %v", err)
+			}
+			if got := decoded.ID.Hex(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlexibleInt_UnmarshalBSONValue(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  bson.M
+		want int
+	}{
+		{name: "int32 rating", doc: bson.M{"rating": int32(4)}, want: 4},
+		{name: "int64 rating", doc: bson.M{"rating": int64(5)}, want: 5},
+		{name: "legacy float rating", doc: bson.M{"rating": float64(3.0)}, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := bson.Marshal(tt.doc)
+			if err != nil {
+				t.Fatalf("bson.Marshal: %v", err)
+			}
+
+			var decoded struct {
+				Rating FlexibleInt `bson:"rating"`
+			}
+			if err := bson.Unmarshal(raw, &decoded); err != nil {
+				t.Fatalf("bson.Unmarshal: %v", err)
+			}
+			if int(decoded.Rating) != tt.want {
+				t.Errorf("got %d, want %d", int(decoded.Rating), tt.want)
+			}
+		})
+	}
+}
+
+func TestFlexibleInt_UnmarshalBSONValue_rejectsUnsupportedType(t *testing.T) {
+	raw, err := bson.Marshal(bson.M{"rating": "five"})
+	if err != nil {
+		t.Fatalf("bson.Marshal: %v", err)
+	}
+
+	var decoded struct {
+		Rating FlexibleInt `bson:"rating"`
+	}
+	if err := bson.Unmarshal(raw, &decoded); err == nil {
+		t.Fatal("expected an error decoding a string rating, got nil")
+	}
+}
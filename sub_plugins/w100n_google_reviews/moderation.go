@@ -0,0 +1,230 @@
+package google_reviews
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"web100now-clients-platform/app/graph/model"
+	"web100now-clients-platform/core/db/utils"
+	"web100now-clients-platform/core/logger"
+	"web100now-clients-platform/core/middleware"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const reviewModerationLogCollection = "review_moderation_log"
+
+// rolesAllowedToModerate are the client roles permitted to call the
+// moderation mutations below. Extend this list rather than bypassing
+// requireModeratorRole when a new role needs access.
+var rolesAllowedToModerate = []string{"admin", "moderator"}
+
+// ModerationLogEntry is one append-only row in review_moderation_log,
+// recording who changed a review's moderation state, when, and why.
+type ModerationLogEntry struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	PlaceID        string             `bson:"place_id"`
+	IDReview       string             `bson:"id_review"`
+	Actor          string             `bson:"actor"`
+	Action         string             `bson:"action"`
+	PreviousStatus bool               `bson:"previous_status"`
+	NewStatus      bool               `bson:"new_status"`
+	Reason         string             `bson:"reason,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at"`
+}
+
+// MutationResolver resolves the GraphQL mutations that change a review's
+// moderation state (hide/restore/flag). It is kept separate from Resolver,
+// which only ever reads reviews, so that the read and write paths can be
+// wired into the GraphQL schema independently.
+type MutationResolver struct{}
+
+// NewMutationResolver returns a new MutationResolver.
+func NewMutationResolver() *MutationResolver {
+	return &MutationResolver{}
+}
+
+// requireModeratorRole denies the call unless ctx carries ClientData with a
+// role in rolesAllowedToModerate, failing closed when no ClientData is
+// present at all.
+func requireModeratorRole(ctx context.Context) (*middleware.ClientData, error) {
+	clientDataAny := ctx.Value(middleware.ClientDataKey)
+	if clientDataAny == nil {
+		return nil, fmt.Errorf("moderation requires an authenticated client")
+	}
+	cd, ok := clientDataAny.(*middleware.ClientData)
+	if !ok {
+		return nil, fmt.Errorf("moderation requires an authenticated client")
+	}
+	for _, role := range rolesAllowedToModerate {
+		if cd.Role == role {
+			return cd, nil
+		}
+	}
+	return nil, fmt.Errorf("client role %q is not permitted to moderate reviews", cd.Role)
+}
+
+// reviewActor identifies the acting client for a ModerationLogEntry,
+// preferring ClientID since it is the stable Mongo-level identifier.
+func reviewActor(cd *middleware.ClientData) string {
+	if cd.ClientID != "" {
+		return cd.ClientID
+	}
+	return cd.ClientName
+}
+
+// fetchReviewSubdocument returns the single review matching placeID/idReview
+// out of google_reviews.reviews, via a positional ($) projection.
+func fetchReviewSubdocument(ctx context.Context, collection *mongo.Collection, placeID, idReview string) (map[string]interface{}, error) {
+	filter := bson.M{"place_id": placeID, "reviews.id_review": idReview}
+
+	var doc struct {
+		Reviews []map[string]interface{} `bson:"reviews"`
+	}
+	err := collection.FindOne(ctx, filter, options.FindOne().SetProjection(bson.M{"reviews.$": 1})).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("no review %q found for place %q", idReview, placeID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up review %q for place %q: %w", idReview, placeID, err)
+	}
+	if len(doc.Reviews) == 0 {
+		return nil, fmt.Errorf("no review %q found for place %q", idReview, placeID)
+	}
+	return doc.Reviews[0], nil
+}
+
+// logModeration appends one audit row to review_moderation_log. A failure to
+// log is treated the same way repo-wide logging failures are: surfaced to
+// the caller as the operation's error, since an unaudited moderation change
+// is not an acceptable partial success.
+func logModeration(ctx context.Context, db *mongo.Database, entry ModerationLogEntry) error {
+	entry.CreatedAt = time.Now()
+	_, err := db.Collection(reviewModerationLogCollection).InsertOne(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("failed to record moderation log entry for review %q: %w", entry.IDReview, err)
+	}
+	return nil
+}
+
+// SetReviewStatus hides or restores a single review (status == false hides
+// it from FetchGoogleReviewsJSON's filtered output) and records the change
+// in review_moderation_log.
+func (r *MutationResolver) SetReviewStatus(ctx context.Context, placeID string, idReview string, status bool) (*model.Review, error) {
+	cd, err := requireModeratorRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		logger.LogError("[SetReviewStatus] Failed to connect to database", err)
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	collection := db.Collection("google_reviews")
+	previous, err := fetchReviewSubdocument(ctx, collection, placeID, idReview)
+	if err != nil {
+		return nil, err
+	}
+	previousStatus, _ := previous["status"].(bool)
+
+	_, err = collection.UpdateOne(ctx,
+		bson.M{"place_id": placeID, "reviews.id_review": idReview},
+		bson.M{"$set": bson.M{"reviews.$.status": status}},
+	)
+	if err != nil {
+		logger.LogError("[SetReviewStatus] Failed to update review status", err)
+		return nil, fmt.Errorf("failed to set status for review %q: %w", idReview, err)
+	}
+
+	if err := logModeration(ctx, db, ModerationLogEntry{
+		PlaceID:        placeID,
+		IDReview:       idReview,
+		Actor:          reviewActor(cd),
+		Action:         "set_status",
+		PreviousStatus: previousStatus,
+		NewStatus:      status,
+	}); err != nil {
+		logger.LogError("[SetReviewStatus] Failed to log moderation change", err)
+		return nil, err
+	}
+
+	previous["status"] = status
+	return convertMapToReview(previous)
+}
+
+// FlagReview marks a review with a moderation reason without changing its
+// visibility, so a human moderator can follow up before deciding to hide it.
+func (r *MutationResolver) FlagReview(ctx context.Context, placeID string, idReview string, reason string) (*model.Review, error) {
+	cd, err := requireModeratorRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		logger.LogError("[FlagReview] Failed to connect to database", err)
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	collection := db.Collection("google_reviews")
+	previous, err := fetchReviewSubdocument(ctx, collection, placeID, idReview)
+	if err != nil {
+		return nil, err
+	}
+	previousStatus, _ := previous["status"].(bool)
+
+	_, err = collection.UpdateOne(ctx,
+		bson.M{"place_id": placeID, "reviews.id_review": idReview},
+		bson.M{"$set": bson.M{"reviews.$.moderation_reason": reason}},
+	)
+	if err != nil {
+		logger.LogError("[FlagReview] Failed to flag review", err)
+		return nil, fmt.Errorf("failed to flag review %q: %w", idReview, err)
+	}
+
+	if err := logModeration(ctx, db, ModerationLogEntry{
+		PlaceID:        placeID,
+		IDReview:       idReview,
+		Actor:          reviewActor(cd),
+		Action:         "flag",
+		PreviousStatus: previousStatus,
+		NewStatus:      previousStatus,
+		Reason:         reason,
+	}); err != nil {
+		logger.LogError("[FlagReview] Failed to log moderation change", err)
+		return nil, err
+	}
+
+	previous["moderation_reason"] = reason
+	return convertMapToReview(previous)
+}
+
+// BulkSetReviewStatus applies SetReviewStatus to every idReview in
+// idReviews, continuing past individual failures so one bad ID does not
+// block the rest of the batch; any failures are returned joined together
+// once the batch has finished.
+func (r *MutationResolver) BulkSetReviewStatus(ctx context.Context, placeID string, idReviews []string, status bool) ([]*model.Review, error) {
+	reviews := make([]*model.Review, 0, len(idReviews))
+	var failures []string
+
+	for _, idReview := range idReviews {
+		review, err := r.SetReviewStatus(ctx, placeID, idReview, status)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", idReview, err))
+			continue
+		}
+		reviews = append(reviews, review)
+	}
+
+	if len(failures) > 0 {
+		return reviews, fmt.Errorf("failed to update %d of %d review(s): %s", len(failures), len(idReviews), strings.Join(failures, "; "))
+	}
+	return reviews, nil
+}
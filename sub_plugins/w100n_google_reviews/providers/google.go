@@ -0,0 +1,96 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GoogleProvider is the ReviewProvider backed by this repo's own Mongo
+// google_reviews collection - the same data sync.Worker keeps fresh and
+// functions.FetchGoogleReviewsJSON reads for the unpaginated GoogleReviews
+// query.
+type GoogleProvider struct {
+	Collection *mongo.Collection
+}
+
+// NewGoogleProvider returns a GoogleProvider reading from db's google_reviews
+// collection.
+func NewGoogleProvider(db *mongo.Database) *GoogleProvider {
+	return &GoogleProvider{Collection: db.Collection("google_reviews")}
+}
+
+// Name implements ReviewProvider.
+func (p *GoogleProvider) Name() string {
+	return "google"
+}
+
+// RateLimit implements ReviewProvider. Reads come from our own Mongo
+// collection, not Google's API, so there is no external rate limit to
+// respect here.
+func (p *GoogleProvider) RateLimit() RateSpec {
+	return RateSpec{RequestsPerMinute: 0, Burst: 0}
+}
+
+// Fetch implements ReviewProvider, returning ref.ID's stored reviews.
+func (p *GoogleProvider) Fetch(ctx context.Context, ref PlaceRef) ([]Review, error) {
+	var doc struct {
+		Reviews []map[string]interface{} `bson:"reviews"`
+	}
+	err := p.Collection.FindOne(ctx, bson.M{"place_id": ref.ID}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch google reviews for place %q: %w", ref.ID, err)
+	}
+
+	reviews := make([]Review, 0, len(doc.Reviews))
+	for _, m := range doc.Reviews {
+		// Same status == true && non-empty text rule filterReviewDocs applies
+		// to the other google_reviews read paths, so a review a moderator has
+		// hidden via SetReviewStatus doesn't resurface here.
+		if !boolField(m, "status") || stringField(m, "text") == "" {
+			continue
+		}
+		reviews = append(reviews, Review{
+			Source:                  p.Name(),
+			IDReview:                stringField(m, "id_review"),
+			AuthorName:              stringField(m, "author_name"),
+			Rating:                  intField(m, "rating"),
+			Text:                    stringField(m, "text"),
+			RelativeTimeDescription: stringField(m, "relative_time_description"),
+			RetrievalDate:           stringField(m, "retrieval_date"),
+			Status:                  boolField(m, "status"),
+			NReviewUser:             stringField(m, "n_review_user"),
+			NPhotoUser:              stringField(m, "n_photo_user"),
+			URLUser:                 stringField(m, "url_user"),
+		})
+	}
+	return reviews, nil
+}
+
+func stringField(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func boolField(m map[string]interface{}, key string) bool {
+	b, _ := m[key].(bool)
+	return b
+}
+
+func intField(m map[string]interface{}, key string) int {
+	switch v := m[key].(type) {
+	case int32:
+		return int(v)
+	case int:
+		return v
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
+}
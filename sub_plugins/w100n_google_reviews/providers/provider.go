@@ -0,0 +1,55 @@
+// Package providers defines the pluggable review-source abstraction that
+// lets the google_reviews sub_plugin aggregate reviews from more than just
+// Google: each external source (Google, TripAdvisor, Yelp, ...) implements
+// ReviewProvider and is normalized to the same Review shape so callers can
+// merge, dedupe and rank across sources without caring where a review came
+// from.
+package providers
+
+import "context"
+
+// RateSpec describes the rate limit a ReviewProvider's upstream source
+// enforces, so callers (and future backoff/scheduling logic) know how hard
+// they can poll it.
+type RateSpec struct {
+	RequestsPerMinute int
+	Burst             int
+}
+
+// PlaceRef identifies the place to fetch reviews for in a source-specific
+// way: Google keys reviews by place_id, TripAdvisor by location ID, Yelp by
+// business ID. ID is whichever of those the provider being called expects.
+type PlaceRef struct {
+	ID string
+}
+
+// Review is one normalized review, independent of which ReviewProvider
+// produced it. Source records which provider it came from so
+// AggregatedReviews can dedupe by (Source, IDReview) across providers that
+// might otherwise reuse the same review ID.
+type Review struct {
+	Source                  string
+	IDReview                string
+	AuthorName              string
+	Rating                  int
+	Text                    string
+	RelativeTimeDescription string
+	RetrievalDate           string
+	Status                  bool
+	NReviewUser             string
+	NPhotoUser              string
+	URLUser                 string
+}
+
+// ReviewProvider fetches the current reviews for a place from one external
+// review source.
+type ReviewProvider interface {
+	// Fetch returns ref's current reviews from this provider's source.
+	Fetch(ctx context.Context, ref PlaceRef) ([]Review, error)
+	// Name identifies this provider, used as Review.Source and as the cache
+	// key's source component.
+	Name() string
+	// RateLimit reports the upstream rate limit this provider's Fetch calls
+	// are subject to.
+	RateLimit() RateSpec
+}
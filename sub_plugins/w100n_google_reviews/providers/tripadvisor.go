@@ -0,0 +1,91 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const tripAdvisorReviewsURLFormat = "https://api.content.tripadvisor.com/api/v1/location/%s/reviews"
+
+type tripAdvisorReviewsResponse struct {
+	Data []struct {
+		ID          int    `json:"id"`
+		Rating      int    `json:"rating"`
+		Text        string `json:"text"`
+		PublishedAt string `json:"published_date"`
+		User        struct {
+			Username string `json:"username"`
+		} `json:"user"`
+	} `json:"data"`
+}
+
+// TripAdvisorProvider fetches reviews through the TripAdvisor Content API.
+type TripAdvisorProvider struct {
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// NewTripAdvisorProvider returns a TripAdvisorProvider using apiKey and a 10
+// second per-request timeout, matching sync.NewPlacesClient's default.
+func NewTripAdvisorProvider(apiKey string) *TripAdvisorProvider {
+	return &TripAdvisorProvider{
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name implements ReviewProvider.
+func (p *TripAdvisorProvider) Name() string {
+	return "tripadvisor"
+}
+
+// RateLimit implements ReviewProvider, using TripAdvisor's documented Content
+// API default of 50 requests per second per key; Burst is kept conservative
+// since this provider has no backoff logic of its own yet.
+func (p *TripAdvisorProvider) RateLimit() RateSpec {
+	return RateSpec{RequestsPerMinute: 3000, Burst: 50}
+}
+
+// Fetch implements ReviewProvider, returning ref.ID's (a TripAdvisor
+// location ID) current reviews.
+func (p *TripAdvisorProvider) Fetch(ctx context.Context, ref PlaceRef) ([]Review, error) {
+	url := fmt.Sprintf(tripAdvisorReviewsURLFormat, ref.ID) + "?key=" + p.APIKey + "&language=en"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tripadvisor reviews request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("tripadvisor reviews request failed for %q: %w", ref.ID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tripadvisor reviews request for %q returned status %d", ref.ID, resp.StatusCode)
+	}
+
+	var parsed tripAdvisorReviewsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode tripadvisor reviews response for %q: %w", ref.ID, err)
+	}
+
+	reviews := make([]Review, 0, len(parsed.Data))
+	for _, d := range parsed.Data {
+		reviews = append(reviews, Review{
+			Source:        p.Name(),
+			IDReview:      fmt.Sprintf("%d", d.ID),
+			AuthorName:    d.User.Username,
+			Rating:        d.Rating,
+			Text:          d.Text,
+			RetrievalDate: d.PublishedAt,
+			Status:        true,
+		})
+	}
+	return reviews, nil
+}
@@ -0,0 +1,92 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const yelpReviewsURLFormat = "https://api.yelp.com/v3/businesses/%s/reviews"
+
+type yelpReviewsResponse struct {
+	Reviews []struct {
+		ID          string `json:"id"`
+		Rating      int    `json:"rating"`
+		Text        string `json:"text"`
+		TimeCreated string `json:"time_created"`
+		User        struct {
+			Name string `json:"name"`
+		} `json:"user"`
+	} `json:"reviews"`
+}
+
+// YelpProvider fetches reviews through the Yelp Fusion API.
+type YelpProvider struct {
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// NewYelpProvider returns a YelpProvider using apiKey and a 10 second
+// per-request timeout, matching sync.NewPlacesClient's default.
+func NewYelpProvider(apiKey string) *YelpProvider {
+	return &YelpProvider{
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name implements ReviewProvider.
+func (p *YelpProvider) Name() string {
+	return "yelp"
+}
+
+// RateLimit implements ReviewProvider, using Yelp Fusion's documented
+// default of 5000 requests/day per key; expressed per-minute here so it is
+// comparable with the other providers' RateSpec values.
+func (p *YelpProvider) RateLimit() RateSpec {
+	return RateSpec{RequestsPerMinute: 3, Burst: 5}
+}
+
+// Fetch implements ReviewProvider, returning ref.ID's (a Yelp business ID)
+// current reviews. Yelp's public Reviews endpoint only ever returns up to 3
+// excerpted reviews per business; this is an API limitation, not a bug here.
+func (p *YelpProvider) Fetch(ctx context.Context, ref PlaceRef) ([]Review, error) {
+	url := fmt.Sprintf(yelpReviewsURLFormat, ref.ID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build yelp reviews request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.APIKey)
+
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("yelp reviews request failed for %q: %w", ref.ID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("yelp reviews request for %q returned status %d", ref.ID, resp.StatusCode)
+	}
+
+	var parsed yelpReviewsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode yelp reviews response for %q: %w", ref.ID, err)
+	}
+
+	reviews := make([]Review, 0, len(parsed.Reviews))
+	for _, rv := range parsed.Reviews {
+		reviews = append(reviews, Review{
+			Source:        p.Name(),
+			IDReview:      rv.ID,
+			AuthorName:    rv.User.Name,
+			Rating:        rv.Rating,
+			Text:          rv.Text,
+			RetrievalDate: rv.TimeCreated,
+			Status:        true,
+		})
+	}
+	return reviews, nil
+}
@@ -2,14 +2,14 @@ package google_reviews
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"strings"
 
 	"web100now-clients-platform/app/graph/model"
 	"web100now-clients-platform/app/plugins/w100n_structure_websites/sub_plugins/w100n_google_reviews/functions"
 	"web100now-clients-platform/core/db/utils"
 	"web100now-clients-platform/core/logger"
-
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Resolver – резолвер для Google Reviews.
@@ -32,28 +32,17 @@ func (r *Resolver) GoogleReviews(ctx context.Context) (*model.GoogleReviewsRespo
 
 	logger.LogInfo("[GoogleReviews] MongoDB connection established successfully")
 
-	result, err := functions.FetchGoogleReviewsJSON(ctx, db)
+	docs, err := functions.FetchGoogleReviewsJSON(ctx, db)
 	if err != nil {
 		logger.LogError("[GoogleReviews] Error fetching google reviews data", err)
 		return nil, fmt.Errorf("error fetching google reviews: %w", err)
 	}
 
-	docs, ok := result["google_reviews"]
-	if !ok {
-		logger.LogError("[GoogleReviews] google_reviews key not found in result", fmt.Errorf("key missing"))
-		return nil, fmt.Errorf("google_reviews key not found in result")
-	}
-
 	logger.LogInfo(fmt.Sprintf("[GoogleReviews] Found %d review document(s) in database", len(docs)))
 
-	var reviews []*model.GoogleReview
-	for i, doc := range docs {
-		review, err := convertDocToGoogleReview(doc)
-		if err != nil {
-			logger.LogError(fmt.Sprintf("[GoogleReviews] Failed to convert document %d to GoogleReview", i), err)
-			return nil, err
-		}
-		reviews = append(reviews, review)
+	reviews := make([]*model.GoogleReview, 0, len(docs))
+	for _, doc := range docs {
+		reviews = append(reviews, googleReviewDocToModel(doc))
 	}
 
 	logger.LogInfo(fmt.Sprintf("[GoogleReviews] Successfully converted %d review(s) to GraphQL model", len(reviews)))
@@ -63,64 +52,48 @@ func (r *Resolver) GoogleReviews(ctx context.Context) (*model.GoogleReviewsRespo
 	}, nil
 }
 
-// convertDocToGoogleReview конвертує документ із MongoDB у GraphQL-модель GoogleReview.
-func convertDocToGoogleReview(doc map[string]interface{}) (*model.GoogleReview, error) {
-	var oid primitive.ObjectID
-	// Спробуємо отримати _id як primitive.ObjectID
-	if idVal, ok := doc["_id"].(primitive.ObjectID); ok {
-		oid = idVal
-	} else if str, ok := doc["_id"].(string); ok {
-		var err error
-		oid, err = primitive.ObjectIDFromHex(str)
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert _id to ObjectID: %w", err)
-		}
-	} else {
-		return nil, fmt.Errorf("unexpected type for _id")
+// googleReviewDocToModel maps a typed functions.GoogleReviewDoc straight to
+// the GraphQL GoogleReview model. This replaces the old
+// convertDocToGoogleReview, which type-switched a map[string]interface{}
+// field by field (including three branches just to cope with
+// int32/int/float64 ratings) and silently produced a zero field on a type
+// mismatch instead of failing at decode time.
+func googleReviewDocToModel(doc functions.GoogleReviewDoc) *model.GoogleReview {
+	reviews := make([]*model.Review, 0, len(doc.Reviews))
+	for _, r := range doc.Reviews {
+		reviews = append(reviews, reviewDocToModel(r))
 	}
 
-	name, _ := doc["name"].(string)
-
-	var rating int
-	if r, ok := doc["rating"].(int32); ok {
-		rating = int(r)
-	} else if r, ok := doc["rating"].(int); ok {
-		rating = r
-	} else if r, ok := doc["rating"].(float64); ok {
-		rating = int(r)
+	return &model.GoogleReview{
+		ID:      doc.ID.Hex(),
+		Name:    doc.Name,
+		Rating:  int(doc.Rating),
+		Reviews: reviews,
 	}
+}
 
-	// Обробка масиву reviews
-	var reviews []*model.Review
-	switch revs := doc["reviews"].(type) {
-	case primitive.A:
-		for _, r := range revs {
-			if reviewMap, ok := r.(map[string]interface{}); ok {
-				review, err := convertMapToReview(reviewMap)
-				if err != nil {
-					return nil, err
-				}
-				reviews = append(reviews, review)
-			}
-		}
-	case []interface{}:
-		for _, r := range revs {
-			if reviewMap, ok := r.(map[string]interface{}); ok {
-				review, err := convertMapToReview(reviewMap)
-				if err != nil {
-					return nil, err
-				}
-				reviews = append(reviews, review)
-			}
-		}
+// reviewDocToModel maps a typed functions.ReviewDoc straight to the GraphQL
+// Review model.
+func reviewDocToModel(r functions.ReviewDoc) *model.Review {
+	source := r.Source
+	if source == "" {
+		source = string(ReviewSourceGoogle)
 	}
 
-	return &model.GoogleReview{
-		ID:      oid.Hex(),
-		Name:    name,
-		Rating:  rating,
-		Reviews: reviews,
-	}, nil
+	return &model.Review{
+		AuthorName:              r.AuthorName,
+		Rating:                  int(r.Rating),
+		Text:                    r.Text,
+		RelativeTimeDescription: r.RelativeTimeDescription,
+		RetrievalDate:           r.RetrievalDate,
+		Status:                  r.Status,
+		IDReview:                r.IDReview,
+		NReviewUser:             r.NReviewUser,
+		NPhotoUser:              r.NPhotoUser,
+		URLUser:                 r.URLUser,
+		ModerationReason:        r.ModerationReason,
+		Source:                  source,
+	}
 }
 
 // convertMapToReview конвертує карту даних у GraphQL-модель Review.
@@ -142,6 +115,14 @@ func convertMapToReview(m map[string]interface{}) (*model.Review, error) {
 	nReviewUser, _ := m["n_review_user"].(string)
 	nPhotoUser, _ := m["n_photo_user"].(string)
 	urlUser, _ := m["url_user"].(string)
+	moderationReason, _ := m["moderation_reason"].(string)
+
+	// source defaults to "google" since documents written before the
+	// AggregatedReviews provider refactor never stored this field.
+	source, _ := m["source"].(string)
+	if source == "" {
+		source = string(ReviewSourceGoogle)
+	}
 
 	return &model.Review{
 		AuthorName:              authorName,
@@ -154,5 +135,110 @@ func convertMapToReview(m map[string]interface{}) (*model.Review, error) {
 		NReviewUser:             nReviewUser,
 		NPhotoUser:              nPhotoUser,
 		URLUser:                 urlUser,
+		ModerationReason:        moderationReason,
+		Source:                  source,
 	}, nil
 }
+
+// GoogleReviewsConnectionInput – параметри пагінованого запиту відгуків:
+// фільтр, сортування, розмір сторінки (First) та опційний курсор (After),
+// отриманий з попередньої сторінки. Тимчасова заміна моделі з app/graph/model,
+// поки відповідне поле не додане до GraphQL-схеми.
+type GoogleReviewsConnectionInput struct {
+	Filter functions.ReviewFilter
+	Sort   functions.ReviewSort
+	First  int
+	After  *string
+}
+
+// PageInfo – тимчасова заміна стандартного GraphQL-типу PageInfo, поки він не
+// з'явився в app/graph/model для цього запиту.
+type PageInfo struct {
+	HasNextPage bool
+	EndCursor   *string
+}
+
+// GoogleReviewEdge – одна пара "відгук + його курсор" у сторінці
+// GoogleReviewsConnectionResult.
+type GoogleReviewEdge struct {
+	Node   *model.Review
+	Cursor string
+}
+
+// GoogleReviewsConnectionResult – сторінка відгуків у вигляді Relay-подібного
+// connection (edges/pageInfo/totalCount).
+type GoogleReviewsConnectionResult struct {
+	Edges      []*GoogleReviewEdge
+	PageInfo   *PageInfo
+	TotalCount int64
+}
+
+// GoogleReviewsConnection повертає пагіновану, відфільтровану та відсортовану
+// сторінку відгуків поверх functions.FetchGoogleReviewsConnection. Це готова
+// реалізація для майбутнього поля googleReviewsConnection у GraphQL-схемі —
+// наразі до неї немає відповідного поля/моделі в app/graph/model, тож метод
+// існує окремо від GoogleReviews і нічого в ньому не змінює.
+func (r *Resolver) GoogleReviewsConnection(ctx context.Context, input GoogleReviewsConnectionInput) (*GoogleReviewsConnectionResult, error) {
+	db, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		logger.LogError("[GoogleReviewsConnection] Failed to connect to database", err)
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	var after *functions.ReviewCursor
+	if input.After != nil {
+		after, err = decodeReviewCursor(*input.After)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+	}
+
+	page, err := functions.FetchGoogleReviewsConnection(ctx, db, input.Filter, input.Sort, input.First, after)
+	if err != nil {
+		logger.LogError("[GoogleReviewsConnection] Error fetching google reviews page", err)
+		return nil, fmt.Errorf("error fetching google reviews: %w", err)
+	}
+
+	edges := make([]*GoogleReviewEdge, 0, len(page.Reviews))
+	for _, m := range page.Reviews {
+		review, err := convertMapToReview(m)
+		if err != nil {
+			return nil, err
+		}
+		edges = append(edges, &GoogleReviewEdge{
+			Node:   review,
+			Cursor: encodeReviewCursor(review.RetrievalDate, review.IDReview),
+		})
+	}
+
+	pageInfo := &PageInfo{HasNextPage: page.HasNextPage}
+	if len(edges) > 0 {
+		endCursor := edges[len(edges)-1].Cursor
+		pageInfo.EndCursor = &endCursor
+	}
+
+	return &GoogleReviewsConnectionResult{
+		Edges:      edges,
+		PageInfo:   pageInfo,
+		TotalCount: page.TotalCount,
+	}, nil
+}
+
+// encodeReviewCursor base64-кодує {retrievalDate, idReview} у опаковий курсор.
+func encodeReviewCursor(retrievalDate, idReview string) string {
+	raw := retrievalDate + "|" + idReview
+	return base64.StdEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeReviewCursor розкодовує курсор, створений encodeReviewCursor.
+func decodeReviewCursor(cursor string) (*functions.ReviewCursor, error) {
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cursor: %w", err)
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	return &functions.ReviewCursor{RetrievalDate: parts[0], IDReview: parts[1]}, nil
+}
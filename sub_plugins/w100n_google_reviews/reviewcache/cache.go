@@ -0,0 +1,63 @@
+// Package reviewcache provides a small in-memory, TTL-bounded cache for
+// provider review fetches, keyed by (source, place ref), so that repeated
+// AggregatedReviews resolver calls for the same place do not refetch from
+// every provider on each request.
+package reviewcache
+
+import (
+	"sync"
+	"time"
+
+	"web100now-clients-platform/app/plugins/w100n_structure_websites/sub_plugins/w100n_google_reviews/providers"
+)
+
+// Key identifies one provider's review set for one place.
+type Key struct {
+	Source   string
+	PlaceRef string
+}
+
+type entry struct {
+	reviews   []providers.Review
+	expiresAt time.Time
+}
+
+// Cache is a concurrency-safe, TTL-bounded cache of provider review fetches.
+// The zero value is not usable; construct one with NewCache.
+type Cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[Key]entry
+}
+
+// NewCache returns a Cache whose entries expire ttl after being Set.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		entries: make(map[Key]entry),
+	}
+}
+
+// Get returns key's cached reviews and true, or (nil, false) if key is
+// missing or its entry has expired.
+func (c *Cache) Get(key Key) ([]providers.Review, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expiresAt) {
+		return nil, false
+	}
+	return e.reviews, true
+}
+
+// Set stores reviews under key, expiring after the Cache's configured TTL.
+func (c *Cache) Set(key Key, reviews []providers.Review) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry{
+		reviews:   reviews,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+}
@@ -0,0 +1,30 @@
+package sync
+
+import "time"
+
+// Backoff computes exponential retry delays for quota/rate-limit errors,
+// capped at Max.
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// DefaultBackoff starts at 30s and doubles up to a 30 minute cap, which
+// comfortably outlasts Google Places' per-minute quota windows.
+var DefaultBackoff = Backoff{Base: 30 * time.Second, Max: 30 * time.Minute}
+
+// Delay returns the backoff delay for the attempt'th consecutive failure
+// (attempt starting at 1).
+func (b Backoff) Delay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := b.Base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= b.Max {
+			return b.Max
+		}
+	}
+	return delay
+}
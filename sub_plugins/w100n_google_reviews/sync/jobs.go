@@ -0,0 +1,133 @@
+// Package sync implements a small job-queue abstraction and background
+// worker that keep the google_reviews collection fresh by periodically
+// polling the Google Places Details API for each tracked place_id.
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// syncJobsCollection is the name of the collection Queue reads from and
+// writes to.
+const syncJobsCollection = "google_reviews_sync_jobs"
+
+// Job is one tracked Google Places place_id, its polling interval, and its
+// lease/audit state.
+type Job struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	PlaceID       string             `bson:"place_id"`
+	Interval      time.Duration      `bson:"interval"`
+	LeasedUntil   time.Time          `bson:"leased_until"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at"`
+	LastSync      time.Time          `bson:"last_sync"`
+	LastError     string             `bson:"last_error"`
+}
+
+// Queue is a small job-queue abstraction over google_reviews_sync_jobs:
+// enqueue, claim-with-lease, complete/fail. It is not tied to review syncing
+// specifically, so other sub_plugins can reuse the same lifecycle for their
+// own scheduled background work instead of rolling their own lease logic.
+type Queue struct {
+	collection *mongo.Collection
+}
+
+// NewQueue returns a Queue backed by reviewsDB's google_reviews_sync_jobs
+// collection.
+func NewQueue(reviewsDB *mongo.Database) *Queue {
+	return &Queue{collection: reviewsDB.Collection(syncJobsCollection)}
+}
+
+// Enqueue upserts a job for placeID with the given polling interval. Calling
+// Enqueue on a placeID that already has a job only updates its interval,
+// leaving lease/audit fields untouched.
+func (q *Queue) Enqueue(ctx context.Context, placeID string, interval time.Duration) error {
+	_, err := q.collection.UpdateOne(
+		ctx,
+		bson.M{"place_id": placeID},
+		bson.M{
+			"$set":         bson.M{"place_id": placeID, "interval": interval},
+			"$setOnInsert": bson.M{"next_attempt_at": time.Time{}, "leased_until": time.Time{}},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job for place %q: %w", placeID, err)
+	}
+	return nil
+}
+
+// Claim leases up to limit jobs that are currently due (next_attempt_at has
+// passed and no other worker holds an unexpired lease), extending each
+// claimed job's lease by leaseFor. Every Job Claim returns must eventually be
+// released via Complete or Fail so its lease doesn't dangle until expiry.
+func (q *Queue) Claim(ctx context.Context, limit int, leaseFor time.Duration) ([]Job, error) {
+	now := time.Now()
+	filter := bson.M{
+		"next_attempt_at": bson.M{"$lte": now},
+		"leased_until":    bson.M{"$lte": now},
+	}
+
+	claimed := make([]Job, 0, limit)
+	for len(claimed) < limit {
+		var job Job
+		err := q.collection.FindOneAndUpdate(
+			ctx,
+			filter,
+			bson.M{"$set": bson.M{"leased_until": now.Add(leaseFor)}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&job)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				break
+			}
+			return claimed, fmt.Errorf("failed to claim sync job: %w", err)
+		}
+		claimed = append(claimed, job)
+	}
+	return claimed, nil
+}
+
+// Complete records a successful sync for job, clears its lease, and
+// schedules its next attempt one Interval from now.
+func (q *Queue) Complete(ctx context.Context, job Job) error {
+	now := time.Now()
+	_, err := q.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": job.ID},
+		bson.M{"$set": bson.M{
+			"last_sync":       now,
+			"last_error":      "",
+			"leased_until":    time.Time{},
+			"next_attempt_at": now.Add(job.Interval),
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete sync job for place %q: %w", job.PlaceID, err)
+	}
+	return nil
+}
+
+// Fail records a failed sync for job, clears its lease, and schedules a
+// retry after retryAfter (the caller's backoff policy decides this).
+func (q *Queue) Fail(ctx context.Context, job Job, cause error, retryAfter time.Duration) error {
+	_, err := q.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": job.ID},
+		bson.M{"$set": bson.M{
+			"last_error":      cause.Error(),
+			"leased_until":    time.Time{},
+			"next_attempt_at": time.Now().Add(retryAfter),
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record failure for sync job %q: %w", job.PlaceID, err)
+	}
+	return nil
+}
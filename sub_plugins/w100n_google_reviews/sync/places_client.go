@@ -0,0 +1,85 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const placesDetailsURL = "https://maps.googleapis.com/maps/api/place/details/json"
+
+// ErrQuotaExceeded is returned by PlacesClient.FetchReviews when the Places
+// API reports OVER_QUERY_LIMIT, so callers can back off and retry instead of
+// treating it as a permanent failure.
+var ErrQuotaExceeded = errors.New("google places API quota exceeded")
+
+// PlaceReview is one review as returned by the Places Details API.
+type PlaceReview struct {
+	AuthorName string `json:"author_name"`
+	Rating     int    `json:"rating"`
+	Text       string `json:"text"`
+	Time       int64  `json:"time"`
+}
+
+type placesDetailsResponse struct {
+	Status string `json:"status"`
+	Result struct {
+		Reviews []PlaceReview `json:"reviews"`
+	} `json:"result"`
+	ErrorMessage string `json:"error_message"`
+}
+
+// PlacesClient calls the Google Places Details API to read the current
+// reviews for a place_id.
+type PlacesClient struct {
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// NewPlacesClient returns a PlacesClient using apiKey and a 10 second
+// per-request timeout.
+func NewPlacesClient(apiKey string) *PlacesClient {
+	return &PlacesClient{
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// FetchReviews returns the reviews the Places Details API currently reports
+// for placeID. It returns ErrQuotaExceeded when the API reports
+// OVER_QUERY_LIMIT.
+func (c *PlacesClient) FetchReviews(ctx context.Context, placeID string) ([]PlaceReview, error) {
+	query := url.Values{}
+	query.Set("place_id", placeID)
+	query.Set("fields", "reviews")
+	query.Set("key", c.APIKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, placesDetailsURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build places details request: %w", err)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("places details request failed for %q: %w", placeID, err)
+	}
+	defer resp.Body.Close()
+
+	var parsed placesDetailsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode places details response for %q: %w", placeID, err)
+	}
+
+	switch parsed.Status {
+	case "OK":
+		return parsed.Result.Reviews, nil
+	case "OVER_QUERY_LIMIT":
+		return nil, ErrQuotaExceeded
+	default:
+		return nil, fmt.Errorf("places details error for %q: %s %s", placeID, parsed.Status, parsed.ErrorMessage)
+	}
+}
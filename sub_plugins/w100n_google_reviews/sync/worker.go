@@ -0,0 +1,249 @@
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"web100now-clients-platform/core/logger"
+)
+
+// Worker periodically claims due jobs from a Queue and refreshes their
+// reviews through a PlacesClient, writing the results into the same
+// google_reviews collection functions.FetchGoogleReviewsJSON reads from.
+type Worker struct {
+	Queue        *Queue
+	Client       *PlacesClient
+	Reviews      *mongo.Collection
+	PoolSize     int
+	PollInterval time.Duration
+	LeaseFor     time.Duration
+	Backoff      Backoff
+}
+
+// NewWorker returns a Worker with repo-standard defaults: a 4-worker pool, a
+// 30 second poll interval between claim rounds, a 2 minute lease, and
+// DefaultBackoff for quota errors.
+func NewWorker(queue *Queue, client *PlacesClient, reviewsDB *mongo.Database) *Worker {
+	return &Worker{
+		Queue:        queue,
+		Client:       client,
+		Reviews:      reviewsDB.Collection("google_reviews"),
+		PoolSize:     4,
+		PollInterval: 30 * time.Second,
+		LeaseFor:     2 * time.Minute,
+		Backoff:      DefaultBackoff,
+	}
+}
+
+// Run claims and processes due jobs through a bounded worker pool once per
+// PollInterval until ctx is canceled.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce claims one round of due jobs and processes them through a pool of
+// w.PoolSize goroutines, blocking until every claimed job has been
+// processed.
+func (w *Worker) runOnce(ctx context.Context) {
+	jobs, err := w.Queue.Claim(ctx, w.PoolSize, w.LeaseFor)
+	if err != nil {
+		logger.LogError("[ReviewsWorker] Failed to claim sync jobs", err)
+		return
+	}
+	if len(jobs) == 0 {
+		return
+	}
+
+	jobCh := make(chan Job, len(jobs))
+	for _, job := range jobs {
+		jobCh <- job
+	}
+	close(jobCh)
+
+	var wg sync.WaitGroup
+	for i := 0; i < w.PoolSize; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobCh {
+				w.process(ctx, job)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// process refreshes one job's reviews and reports the outcome back to the
+// queue, retrying quota errors with exponential backoff instead of failing
+// the job on the first quota response.
+func (w *Worker) process(ctx context.Context, job Job) {
+	reviews, err := w.fetchWithBackoff(ctx, job.PlaceID)
+	if err != nil {
+		logger.LogError(fmt.Sprintf("[ReviewsWorker] Sync failed for place %s", job.PlaceID), err)
+		if failErr := w.Queue.Fail(ctx, job, err, w.Backoff.Delay(1)); failErr != nil {
+			logger.LogError("[ReviewsWorker] Failed to record job failure", failErr)
+		}
+		return
+	}
+
+	if err := w.upsertReviews(ctx, job.PlaceID, reviews); err != nil {
+		logger.LogError(fmt.Sprintf("[ReviewsWorker] Failed to upsert reviews for place %s", job.PlaceID), err)
+		if failErr := w.Queue.Fail(ctx, job, err, w.Backoff.Delay(1)); failErr != nil {
+			logger.LogError("[ReviewsWorker] Failed to record job failure", failErr)
+		}
+		return
+	}
+
+	if err := w.Queue.Complete(ctx, job); err != nil {
+		logger.LogError("[ReviewsWorker] Failed to record job completion", err)
+	}
+}
+
+// fetchWithBackoff retries PlacesClient.FetchReviews on ErrQuotaExceeded,
+// sleeping w.Backoff.Delay(attempt) between tries, until it succeeds, hits a
+// non-quota error, or ctx is canceled.
+func (w *Worker) fetchWithBackoff(ctx context.Context, placeID string) ([]PlaceReview, error) {
+	attempt := 0
+	for {
+		reviews, err := w.Client.FetchReviews(ctx, placeID)
+		if err == nil {
+			return reviews, nil
+		}
+		if err != ErrQuotaExceeded {
+			return nil, err
+		}
+
+		attempt++
+		delay := w.Backoff.Delay(attempt)
+		logger.LogInfo(fmt.Sprintf("[ReviewsWorker] Quota exceeded for place %s, backing off %s", placeID, delay))
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// existingReviewDoc is the subset of a stored review this sync needs back in
+// order to avoid clobbering moderation state on re-sync: author_name+time
+// identifies the same review across Places API calls (the API has no stable
+// review id of its own), and everything else is state a human moderator may
+// have set that the Places API knows nothing about.
+type existingReviewDoc struct {
+	AuthorName       string `bson:"author_name"`
+	Time             int64  `bson:"time"`
+	Status           bool   `bson:"status"`
+	IDReview         string `bson:"id_review"`
+	ModerationReason string `bson:"moderation_reason"`
+	NReviewUser      string `bson:"n_review_user"`
+	NPhotoUser       string `bson:"n_photo_user"`
+	URLUser          string `bson:"url_user"`
+	Source           string `bson:"source"`
+}
+
+type existingReviewsDoc struct {
+	Reviews []existingReviewDoc `bson:"reviews"`
+}
+
+// reviewKey identifies the same review across syncs. The Places Details API
+// doesn't return a stable per-review id, but a given reviewer only leaves one
+// review per place, so author+timestamp is stable in practice.
+func reviewKey(authorName string, reviewTime int64) string {
+	return fmt.Sprintf("%s|%d", authorName, reviewTime)
+}
+
+// stableReviewID derives a deterministic id_review for a review the Places
+// API has never given us moderation state for yet, so it gets a stable id
+// from its first sync onward instead of staying empty forever.
+func stableReviewID(placeID, authorName string, reviewTime int64) string {
+	sum := sha256.Sum256([]byte(placeID + "|" + reviewKey(authorName, reviewTime)))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// upsertReviews writes placeID's current review set into the google_reviews
+// document shape functions.FetchGoogleReviewsJSON expects, stamping each
+// review with the retrieval_date this sync ran at. retrieval_date is stored
+// as an RFC3339 string to match the existing field's type (see
+// convertMapToReview's string assertion on it), not a BSON date.
+//
+// A review that already exists in the stored document keeps its status,
+// moderation_reason, id_review, and submitter metadata rather than having
+// them reset to defaults on every sync: those fields are set by moderators
+// and other review sources, not the Places API, and a naive overwrite would
+// silently undo every moderation decision on the next sync.
+func (w *Worker) upsertReviews(ctx context.Context, placeID string, reviews []PlaceReview) error {
+	retrievalDate := time.Now().Format(time.RFC3339)
+
+	var existingDoc existingReviewsDoc
+	if err := w.Reviews.FindOne(ctx, bson.M{"place_id": placeID}).Decode(&existingDoc); err != nil && err != mongo.ErrNoDocuments {
+		return fmt.Errorf("failed to load existing reviews for place %q: %w", placeID, err)
+	}
+	existing := make(map[string]existingReviewDoc, len(existingDoc.Reviews))
+	for _, r := range existingDoc.Reviews {
+		existing[reviewKey(r.AuthorName, r.Time)] = r
+	}
+
+	stamped := make([]bson.M, 0, len(reviews))
+	for _, review := range reviews {
+		entry := bson.M{
+			"author_name":       review.AuthorName,
+			"rating":            review.Rating,
+			"text":              review.Text,
+			"time":              review.Time,
+			"status":            true,
+			"retrieval_date":    retrievalDate,
+			"id_review":         stableReviewID(placeID, review.AuthorName, review.Time),
+			"moderation_reason": "",
+			"n_review_user":     "",
+			"n_photo_user":      "",
+			"url_user":          "",
+			"source":            "",
+		}
+		if prior, ok := existing[reviewKey(review.AuthorName, review.Time)]; ok {
+			entry["status"] = prior.Status
+			entry["moderation_reason"] = prior.ModerationReason
+			entry["n_review_user"] = prior.NReviewUser
+			entry["n_photo_user"] = prior.NPhotoUser
+			entry["url_user"] = prior.URLUser
+			entry["source"] = prior.Source
+			if prior.IDReview != "" {
+				entry["id_review"] = prior.IDReview
+			}
+		}
+		stamped = append(stamped, entry)
+	}
+
+	_, err := w.Reviews.UpdateOne(
+		ctx,
+		bson.M{"place_id": placeID},
+		bson.M{"$set": bson.M{
+			"place_id": placeID,
+			"reviews":  stamped,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert reviews for place %q: %w", placeID, err)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package structure_seo
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"time"
+
+	"web100now-clients-platform/app/graph/model"
+	"web100now-clients-platform/core/db/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// seoVariant mirrors one entry of model.Seo.variants.
+type seoVariant struct {
+	ID              string
+	Weight          int
+	Title           string
+	MetaDescription string
+	OpenGraph       map[string]interface{}
+}
+
+// SeoForRequest deterministically picks a variant for visitorId (stable per
+// visitor, no cookie round-trip needed) by hashing the visitor id modulo the
+// total variant weight, returns the merged *model.Seo, and records an
+// impression in structure_seo_variant_stats.
+func (r *Resolver) SeoForRequest(ctx context.Context, pageKey, visitorID string) (*model.Seo, error) {
+	db, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("DB connect error: %w", err)
+	}
+
+	doc, err := fetchOneSEO(ctx, db, pageKey)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("fetch SEO error: %w", err)
+	}
+
+	seo := convertToModel(doc)
+	variants := parseSeoVariants(doc)
+	if len(variants) == 0 {
+		return seo, nil
+	}
+
+	variant := pickVariant(variants, visitorID)
+	merged := applyVariant(seo, variant)
+
+	if err := recordVariantImpression(ctx, db, pageKey, variant.ID); err != nil {
+		return nil, fmt.Errorf("failed to record impression: %w", err)
+	}
+
+	return merged, nil
+}
+
+// RecordSeoClick records a click against a variant, intended to be called
+// from a client-side beacon on referrer match.
+func (r *Resolver) RecordSeoClick(ctx context.Context, pageKey, variantID string) (bool, error) {
+	db, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		return false, fmt.Errorf("DB connect error: %w", err)
+	}
+
+	if err := bumpVariantStat(ctx, db, pageKey, variantID, "clicks"); err != nil {
+		return false, fmt.Errorf("failed to record click: %w", err)
+	}
+	return true, nil
+}
+
+// SeoVariantReport returns per-variant impressions/clicks/CTR for the date
+// range [from, to), plus a Wilson score lower bound so editors can tell when
+// a variant is statistically ahead rather than just nominally ahead.
+func (r *Resolver) SeoVariantReport(ctx context.Context, pageKey, from, to string) ([]*model.SeoVariantStat, error) {
+	db, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("DB connect error: %w", err)
+	}
+
+	cursor, err := db.Collection("structure_seo_variant_stats").Find(ctx, bson.M{
+		"pageKey": pageKey,
+		"date":    bson.M{"$gte": from, "$lt": to},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query variant stats: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	totals := make(map[string]*model.SeoVariantStat)
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode variant stat: %w", err)
+		}
+		variantID := getString(doc, "variantId")
+		stat, ok := totals[variantID]
+		if !ok {
+			stat = &model.SeoVariantStat{VariantID: variantID}
+			totals[variantID] = stat
+		}
+		if n := getInt(doc, "impressions"); n != nil {
+			stat.Impressions += *n
+		}
+		if n := getInt(doc, "clicks"); n != nil {
+			stat.Clicks += *n
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error while building variant report: %w", err)
+	}
+
+	report := make([]*model.SeoVariantStat, 0, len(totals))
+	for _, stat := range totals {
+		if stat.Impressions > 0 {
+			stat.Ctr = float64(stat.Clicks) / float64(stat.Impressions)
+		}
+		stat.WilsonLowerBound = wilsonLowerBound(stat.Clicks, stat.Impressions)
+		report = append(report, stat)
+	}
+
+	return report, nil
+}
+
+// UpdateSeoVariants replaces the variants array for a page (LOCAL_DEVELOPMENT
+// only), mirroring the guard already in place for other config mutations.
+func (r *Resolver) UpdateSeoVariants(ctx context.Context, pageKey string, variants []map[string]interface{}) (bool, error) {
+	if !localDevelopmentEnabled() {
+		return false, fmt.Errorf("variant mutations are available only when LOCAL_DEVELOPMENT=true")
+	}
+
+	db, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		return false, fmt.Errorf("DB connect error: %w", err)
+	}
+
+	_, err = db.Collection("structure_seo").UpdateOne(
+		ctx,
+		bson.M{"pageKey": pageKey},
+		bson.M{"$set": bson.M{"variants": variants}},
+		options.Update().SetUpsert(false),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update variants: %w", err)
+	}
+	return true, nil
+}
+
+func parseSeoVariants(doc bson.M) []seoVariant {
+	raw := getArray(doc, "variants")
+	variants := make([]seoVariant, 0, len(raw))
+	for _, item := range raw {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		weight := 1
+		if w := getInt(m, "weight"); w != nil && *w > 0 {
+			weight = *w
+		}
+		variants = append(variants, seoVariant{
+			ID:              getString(m, "id"),
+			Weight:          weight,
+			Title:           getString(m, "title"),
+			MetaDescription: getString(m, "metaDescription"),
+			OpenGraph:       getMap(m, "openGraph"),
+		})
+	}
+	return variants
+}
+
+// pickVariant deterministically maps visitorID onto one of variants by
+// hashing it with FNV-1a and reducing modulo the total weight.
+func pickVariant(variants []seoVariant, visitorID string) seoVariant {
+	totalWeight := 0
+	for _, v := range variants {
+		totalWeight += v.Weight
+	}
+	if totalWeight == 0 {
+		return variants[0]
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(visitorID))
+	bucket := int(h.Sum32()) % totalWeight
+	if bucket < 0 {
+		bucket += totalWeight
+	}
+
+	cursor := 0
+	for _, v := range variants {
+		cursor += v.Weight
+		if bucket < cursor {
+			return v
+		}
+	}
+	return variants[len(variants)-1]
+}
+
+func applyVariant(seo *model.Seo, variant seoVariant) *model.Seo {
+	if seo == nil {
+		return nil
+	}
+	merged := *seo
+	if variant.Title != "" {
+		merged.Title = variant.Title
+	}
+	if variant.MetaDescription != "" {
+		merged.MetaDescription = variant.MetaDescription
+	}
+	if variant.OpenGraph != nil {
+		merged.OpenGraph = convertOpenGraph(variant.OpenGraph)
+	}
+	return &merged
+}
+
+func recordVariantImpression(ctx context.Context, db *mongo.Database, pageKey, variantID string) error {
+	return bumpVariantStat(ctx, db, pageKey, variantID, "impressions")
+}
+
+func bumpVariantStat(ctx context.Context, db *mongo.Database, pageKey, variantID, field string) error {
+	date := time.Now().UTC().Format("2006-01-02")
+	_, err := db.Collection("structure_seo_variant_stats").UpdateOne(
+		ctx,
+		bson.M{"pageKey": pageKey, "variantId": variantID, "date": date},
+		bson.M{"$inc": bson.M{field: 1}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// wilsonLowerBound returns the lower bound of the Wilson score confidence
+// interval (95%) for clicks/impressions, which is a more reliable "is this
+// variant actually ahead" signal than raw CTR on small sample sizes.
+func wilsonLowerBound(clicks, impressions int) float64 {
+	if impressions == 0 {
+		return 0
+	}
+	const z = 1.96
+	n := float64(impressions)
+	p := float64(clicks) / n
+
+	denominator := 1 + z*z/n
+	center := p + z*z/(2*n)
+	margin := z * math.Sqrt(p*(1-p)/n+z*z/(4*n*n))
+
+	return (center - margin) / denominator
+}
@@ -0,0 +1,131 @@
+package structure_seo
+
+import (
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+// isSVGSource reports whether the uploaded favicon source is an SVG file,
+// based on filename extension and declared content type.
+func isSVGSource(filename, contentType string) bool {
+	if strings.EqualFold(filepath.Ext(filename), ".svg") {
+		return true
+	}
+	return strings.Contains(strings.ToLower(contentType), "svg")
+}
+
+// writeSVGFaviconAssets copies the uploaded SVG as favicon.svg and derives a
+// monochrome safari-pinned-tab.svg by forcing every fill/stroke color to
+// black, which is what Safari's pinned-tab icon requires.
+func writeSVGFaviconAssets(srcPath, baseDir string) (faviconPath, pinnedTabPath string, err error) {
+	raw, err := os.ReadFile(srcPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read SVG source: %w", err)
+	}
+
+	faviconPath = filepath.Join(baseDir, "favicon.svg")
+	if err := os.WriteFile(faviconPath, raw, 0o644); err != nil {
+		return "", "", fmt.Errorf("failed to write favicon.svg: %w", err)
+	}
+
+	monochrome := monochromeSVG(raw)
+	pinnedTabPath = filepath.Join(baseDir, "safari-pinned-tab.svg")
+	if err := os.WriteFile(pinnedTabPath, monochrome, 0o644); err != nil {
+		return "", "", fmt.Errorf("failed to write safari-pinned-tab.svg: %w", err)
+	}
+
+	return faviconPath, pinnedTabPath, nil
+}
+
+var svgColorAttr = regexp.MustCompile(`(fill|stroke)="[^"]*"`)
+
+// monochromeSVG is a cheap stand-in for rasterizing the SVG and thresholding
+// its luminance: since we already have vector source, forcing every color
+// attribute to black gives Safari the single-color silhouette it expects
+// without pulling in an SVG rasterizer dependency.
+func monochromeSVG(src []byte) []byte {
+	return svgColorAttr.ReplaceAll(src, []byte(`$1="#000000"`))
+}
+
+// generateMaskableIcon pads srcImage onto a transparent size x size canvas so
+// the artwork sits within Android's 80% maskable safe zone.
+func generateMaskableIcon(srcImage image.Image, size int, baseDir string) (name, localPath string, err error) {
+	safeZone := int(float64(size) * 0.8)
+	scaled := imaging.Fit(srcImage, safeZone, safeZone, imaging.Lanczos)
+
+	canvas := image.NewNRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(color.Transparent), image.Point{}, draw.Src)
+
+	offsetX := (size - scaled.Bounds().Dx()) / 2
+	offsetY := (size - scaled.Bounds().Dy()) / 2
+	destRect := image.Rect(offsetX, offsetY, offsetX+scaled.Bounds().Dx(), offsetY+scaled.Bounds().Dy())
+	draw.Draw(canvas, destRect, scaled, image.Point{}, draw.Over)
+
+	name = fmt.Sprintf("android-chrome-maskable-%dx%d.png", size, size)
+	localPath = filepath.Join(baseDir, name)
+	if err := imaging.Save(canvas, localPath, imaging.PNGCompressionLevel(png.BestCompression)); err != nil {
+		return "", "", fmt.Errorf("failed to save %s: %w", name, err)
+	}
+	return name, localPath, nil
+}
+
+// windowsTileTarget describes one Windows tile size to generate for
+// browserconfig.xml.
+type windowsTileTarget struct {
+	Width   int
+	Height  int
+	Name    string
+	TileTag string
+}
+
+var windowsTileTargets = []windowsTileTarget{
+	{70, 70, "mstile-70x70.png", "square70x70logo"},
+	{150, 150, "mstile-150x150.png", "square150x150logo"},
+	{310, 150, "mstile-310x150.png", "wide310x150logo"},
+	{310, 310, "mstile-310x310.png", "square310x310logo"},
+}
+
+// generateWindowsTile renders a single Windows tile image, using Fill so
+// non-square targets (the 310x150 wide tile) crop rather than distort.
+func generateWindowsTile(srcImage image.Image, target windowsTileTarget, baseDir string) (localPath string, err error) {
+	resized := imaging.Fill(srcImage, target.Width, target.Height, imaging.Center, imaging.Lanczos)
+	localPath = filepath.Join(baseDir, target.Name)
+	if err := imaging.Save(resized, localPath, imaging.PNGCompressionLevel(png.BestCompression)); err != nil {
+		return "", fmt.Errorf("failed to save %s: %w", target.Name, err)
+	}
+	return localPath, nil
+}
+
+// buildBrowserConfigXML renders browserconfig.xml referencing all four
+// Windows tile sizes, keyed by CDN path.
+func buildBrowserConfigXML(tileCDNPaths map[string]string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<browserconfig>
+  <msapplication>
+    <tile>
+      <square70x70logo src="%s"/>
+      <square150x150logo src="%s"/>
+      <square310x310logo src="%s"/>
+      <wide310x150logo src="%s"/>
+      <TileColor>#ffffff</TileColor>
+    </tile>
+  </msapplication>
+</browserconfig>
+`, tileCDNPaths["mstile-70x70.png"], tileCDNPaths["mstile-150x150.png"], tileCDNPaths["mstile-310x310.png"], tileCDNPaths["mstile-310x150.png"])
+}
+
+// svgToDataURI is kept for callers that want to inline the monochrome SVG
+// without reading it back off disk.
+func svgToDataURI(svg []byte) string {
+	return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString(svg)
+}
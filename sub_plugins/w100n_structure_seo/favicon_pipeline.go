@@ -0,0 +1,177 @@
+package structure_seo
+
+import (
+	"archive/zip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// faviconStageDeadlines are the per-stage timeouts applied by faviconPipeline.
+// A large upload can otherwise hang any one of these steps (decode, resize,
+// ico encode, zip walk, Mongo update) indefinitely and block the GraphQL
+// request along with it.
+type faviconStageDeadlines struct {
+	Decode      time.Duration
+	Resize      time.Duration
+	IcoEncode   time.Duration
+	ZipWalk     time.Duration
+	MongoUpdate time.Duration
+}
+
+var defaultFaviconStageDeadlines = faviconStageDeadlines{
+	Decode:      10 * time.Second,
+	Resize:      20 * time.Second,
+	IcoEncode:   10 * time.Second,
+	ZipWalk:     30 * time.Second,
+	MongoUpdate: 10 * time.Second,
+}
+
+// faviconPipeline threads a parent context.Context through favicon
+// generation and enforces a deadline per stage, so a single slow stage
+// cannot hang the whole GraphQL request.
+type faviconPipeline struct {
+	parent    context.Context
+	deadlines faviconStageDeadlines
+}
+
+func newFaviconPipeline(ctx context.Context, deadlines faviconStageDeadlines) *faviconPipeline {
+	return &faviconPipeline{parent: ctx, deadlines: deadlines}
+}
+
+// stage runs fn under a context scoped to d, returning context.Canceled (or
+// context.DeadlineExceeded) if fn did not finish in time or the parent
+// context was already canceled.
+func (p *faviconPipeline) stage(d time.Duration, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(p.parent, d)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// contextReader wraps an io.Reader and fails fast once ctx is done, so a
+// copy loop blocked mid-transfer unblocks instead of hanging.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
+// contextAwareCopy is io.CopyBuffer wired through a contextReader so the
+// copy aborts promptly when ctx is canceled instead of blocking on a stalled
+// reader.
+func contextAwareCopy(ctx context.Context, dst io.Writer, src io.Reader) (int64, error) {
+	buf := make([]byte, 32*1024)
+	return io.CopyBuffer(dst, contextReader{ctx: ctx, r: src}, buf)
+}
+
+// runICOStage runs encode (normally createICO for srcImage/targetPath) under
+// the pipeline's IcoEncode deadline, removing the partially-written target
+// file if encode does not finish in time.
+func (p *faviconPipeline) runICOStage(targetPath string, encode func() error) error {
+	err := p.stage(p.deadlines.IcoEncode, func(ctx context.Context) error {
+		return encode()
+	})
+	if err != nil {
+		_ = os.Remove(targetPath)
+		return err
+	}
+	return nil
+}
+
+// runMongoUpdateStage runs update (normally updatePluginFavicons) under the
+// pipeline's MongoUpdate deadline.
+func (p *faviconPipeline) runMongoUpdateStage(update func(ctx context.Context) error) error {
+	return p.stage(p.deadlines.MongoUpdate, update)
+}
+
+// createZipArchiveWithContext mirrors createZipArchive but threads ctx into
+// the filepath.Walk callback and the per-file copy, deleting the
+// partially-written zip and returning context.Canceled on cancellation.
+func createZipArchiveWithContext(ctx context.Context, zipPath, baseDir string) error {
+	if err := os.RemoveAll(zipPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	file, err := os.Create(zipPath)
+	if err != nil {
+		return err
+	}
+
+	writer := zip.NewWriter(file)
+
+	walkErr := filepath.Walk(baseDir, func(path string, info os.FileInfo, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if path == zipPath {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(baseDir, path)
+		if err != nil {
+			return err
+		}
+
+		zipFile, err := writer.Create(filepath.ToSlash(relPath))
+		if err != nil {
+			return err
+		}
+
+		srcFile, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		_, copyErr := contextAwareCopy(ctx, zipFile, srcFile)
+		closeErr := srcFile.Close()
+		if copyErr != nil {
+			return copyErr
+		}
+		return closeErr
+	})
+
+	closeErr := writer.Close()
+	fileCloseErr := file.Close()
+
+	if walkErr != nil {
+		_ = os.Remove(zipPath)
+		if walkErr == context.DeadlineExceeded || walkErr == context.Canceled {
+			return context.Canceled
+		}
+		return walkErr
+	}
+	if closeErr != nil {
+		_ = os.Remove(zipPath)
+		return closeErr
+	}
+	if fileCloseErr != nil {
+		_ = os.Remove(zipPath)
+		return fileCloseErr
+	}
+
+	return nil
+}
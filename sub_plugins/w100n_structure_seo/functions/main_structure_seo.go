@@ -6,6 +6,16 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+
+	"web100now-clients-platform/app/plugins/w100n_structure_websites/sub_plugins/pagination"
+)
+
+// PageSearchOptions and PageResult are aliases for the shared pagination
+// package's types, kept under their historical names here so callers in this
+// package don't need to change.
+type (
+	PageSearchOptions = pagination.SearchOptions
+	PageResult        = pagination.Result
 )
 
 // FetchSEOJSON reads documents from the structure_seo collection and returns them as a map.
@@ -33,6 +43,13 @@ func FetchSEOJSON(ctx context.Context, db *mongo.Database) (map[string][]map[str
 	return results, nil
 }
 
+// FetchSEOJSONPage reads a single page of documents from the structure_seo
+// collection, honoring opts.Query/Project/Sort/Page/Size. A zero-value opts
+// returns the same documents as FetchSEOJSON's "structure_seo" entry.
+func FetchSEOJSONPage(ctx context.Context, db *mongo.Database, opts PageSearchOptions) (*PageResult, error) {
+	return pagination.FetchPage(ctx, db, "structure_seo", opts)
+}
+
 // fetchAllDocuments reads all documents from the specified collection.
 func fetchAllDocuments(ctx context.Context, db *mongo.Database, collectionName string) ([]map[string]interface{}, error) {
 	collection := db.Collection(collectionName)
@@ -0,0 +1,442 @@
+package structure_seo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"reflect"
+	"strings"
+
+	"web100now-clients-platform/app/graph/model"
+	"web100now-clients-platform/core/db/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// headTag is the internal representation of a single <head> tag before it is
+// rendered to HTML or exposed as a JSON entry.
+type headTag struct {
+	Tag     string
+	Attrs   map[string]string
+	Content string
+}
+
+// headTemplateOverride describes one SeoConfig.config.headTemplates entry: raw
+// HTML supplied by an admin, keyed by tag family. applyHeadTemplateOverrides
+// only applies the "all" family, which replaces the whole rendered head;
+// entries for any other family (e.g. "openGraph", "icons") are read but not
+// applied.
+type headTemplateOverride struct {
+	Family string
+	HTML   string
+}
+
+// RenderSeoHead builds the full <head> fragment for a page: meta tags, link
+// tags and JSON-LD scripts derived from the stored model.Seo document and the
+// SeoConfig defaults. format selects the output shape: "html" returns an
+// escaped string ready to drop into a template, "json" returns the ordered
+// tag list so SSR/edge consumers can map over it themselves.
+func (r *Resolver) RenderSeoHead(ctx context.Context, pageKey string, format string) (*model.SeoHeadRender, error) {
+	db, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("DB connect error: %w", err)
+	}
+
+	doc, err := fetchOneSEO(ctx, db, pageKey)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("no SEO document found for pageKey %q", pageKey)
+		}
+		return nil, fmt.Errorf("fetch SEO error: %w", err)
+	}
+	seo := convertToModel(doc)
+
+	configDoc, err := fetchPluginConfig(ctx, db, "structure_seo")
+	var config *model.SeoConfig
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("fetch SEO config error: %w", err)
+		}
+	} else {
+		config = convertConfigToModel(configDoc)
+	}
+
+	overrides := headTemplateOverrides(configDoc)
+
+	var tags []headTag
+	tags = append(tags, buildCoreMetaTags(seo)...)
+	tags = append(tags, buildOpenGraphTags(seo)...)
+	tags = append(tags, buildTwitterTags(seo)...)
+	tags = append(tags, buildFacebookTags(seo)...)
+	tags = append(tags, buildLinkedInTags(seo)...)
+	tags = append(tags, buildDublinCoreTags(seo)...)
+	tags = append(tags, buildAlternateLanguageTags(seo)...)
+	tags = append(tags, buildIconTags(config)...)
+	tags = append(tags, buildStructuredDataTags(seo)...)
+
+	tags = applyHeadTemplateOverrides(tags, overrides)
+
+	switch strings.ToLower(format) {
+	case "", "html":
+		return &model.SeoHeadRender{
+			Format: "html",
+			HTML:   renderTagsToHTML(tags),
+		}, nil
+	case "json":
+		return &model.SeoHeadRender{
+			Format: "json",
+			Tags:   toModelTags(tags),
+		}, nil
+	default:
+		return nil, errors.New("unsupported format: must be \"html\" or \"json\"")
+	}
+}
+
+func buildCoreMetaTags(seo *model.Seo) []headTag {
+	var tags []headTag
+	if seo.Title != "" {
+		tags = append(tags, headTag{Tag: "title", Content: seo.Title})
+	}
+	tags = append(tags, metaTag("description", seo.MetaDescription))
+	if len(seo.Keywords) > 0 {
+		tags = append(tags, metaTag("keywords", strings.Join(seo.Keywords, ", ")))
+	}
+	tags = append(tags, metaTag("author", seo.Author))
+	tags = append(tags, metaTag("viewport", seo.Viewport))
+	tags = append(tags, metaTag("robots", seo.Robots))
+	if seo.Canonical != "" {
+		tags = append(tags, linkTag("canonical", seo.Canonical, ""))
+	}
+	if seo.ThemeColor != "" {
+		tags = append(tags, metaTag("theme-color", seo.ThemeColor))
+	}
+	if seo.Generator != nil {
+		tags = append(tags, metaTag("generator", *seo.Generator))
+	}
+	if seo.Copyright != nil {
+		tags = append(tags, metaTag("copyright", *seo.Copyright))
+	}
+	if seo.ContentLanguage != nil {
+		tags = append(tags, headTag{Tag: "meta", Attrs: map[string]string{"http-equiv": "content-language", "content": *seo.ContentLanguage}})
+	}
+	return tags
+}
+
+func buildOpenGraphTags(seo *model.Seo) []headTag {
+	if seo.OpenGraph == nil {
+		return nil
+	}
+	og := seo.OpenGraph
+	var tags []headTag
+	tags = append(tags, propTag("og:title", og.OgTitle))
+	tags = append(tags, propTag("og:description", og.OgDescription))
+	tags = append(tags, propTag("og:image", og.OgImage))
+	tags = append(tags, propTag("og:url", og.OgURL))
+	tags = append(tags, propTag("og:type", og.OgType))
+	if og.OgLocale != nil {
+		tags = append(tags, propTag("og:locale", *og.OgLocale))
+	}
+	if og.OgSiteName != nil {
+		tags = append(tags, propTag("og:site_name", *og.OgSiteName))
+	}
+	return tags
+}
+
+func buildTwitterTags(seo *model.Seo) []headTag {
+	if seo.TwitterCard == nil {
+		return nil
+	}
+	tc := seo.TwitterCard
+	var tags []headTag
+	tags = append(tags, metaTag("twitter:card", tc.TwitterCard))
+	tags = append(tags, metaTag("twitter:title", tc.TwitterTitle))
+	tags = append(tags, metaTag("twitter:description", tc.TwitterDescription))
+	tags = append(tags, metaTag("twitter:image", tc.TwitterImage))
+	if tc.TwitterSite != nil {
+		tags = append(tags, metaTag("twitter:site", *tc.TwitterSite))
+	}
+	if tc.TwitterCreator != nil {
+		tags = append(tags, metaTag("twitter:creator", *tc.TwitterCreator))
+	}
+	return tags
+}
+
+func buildFacebookTags(seo *model.Seo) []headTag {
+	if seo.Facebook == nil {
+		return nil
+	}
+	fb := seo.Facebook
+	var tags []headTag
+	if fb.FbAppID != nil {
+		tags = append(tags, propTag("fb:app_id", *fb.FbAppID))
+	}
+	if fb.FbAdmins != nil {
+		tags = append(tags, propTag("fb:admins", *fb.FbAdmins))
+	}
+	return tags
+}
+
+func buildLinkedInTags(seo *model.Seo) []headTag {
+	if seo.LinkedIn == nil {
+		return nil
+	}
+	li := seo.LinkedIn
+	var tags []headTag
+	if li.LinkedInOwner != nil {
+		tags = append(tags, metaTag("linkedin:owner", *li.LinkedInOwner))
+	}
+	return tags
+}
+
+func buildDublinCoreTags(seo *model.Seo) []headTag {
+	if seo.DublinCore == nil {
+		return nil
+	}
+	dc := seo.DublinCore
+	var tags []headTag
+	tags = append(tags, metaTag("DC.title", dc.DCTitle))
+	tags = append(tags, metaTag("DC.creator", dc.DCCreator))
+	if dc.DCSubject != nil {
+		tags = append(tags, metaTag("DC.subject", *dc.DCSubject))
+	}
+	if dc.DCDescription != nil {
+		tags = append(tags, metaTag("DC.description", *dc.DCDescription))
+	}
+	return tags
+}
+
+func buildAlternateLanguageTags(seo *model.Seo) []headTag {
+	var tags []headTag
+	for _, alt := range seo.AlternateLanguages {
+		if alt == nil {
+			continue
+		}
+		tags = append(tags, linkTag("alternate", alt.Href, alt.Hreflang))
+	}
+	return tags
+}
+
+// buildIconTags derives Apple/Android/MSTile icon links and the webmanifest
+// reference from the favicon package metadata produced by GenerateSeoFavicons.
+func buildIconTags(config *model.SeoConfig) []headTag {
+	if config == nil {
+		return nil
+	}
+	var tags []headTag
+	if config.FaviconURL != nil && *config.FaviconURL != "" {
+		tags = append(tags, linkTag("icon", *config.FaviconURL, ""))
+	}
+	if config.Webmanifest != nil && *config.Webmanifest != "" {
+		tags = append(tags, linkTag("manifest", *config.Webmanifest, ""))
+	}
+	if config.AppleTouchIcon != nil && *config.AppleTouchIcon != "" {
+		tags = append(tags, linkTag("apple-touch-icon", *config.AppleTouchIcon, ""))
+	}
+	if config.AndroidIcon != nil && *config.AndroidIcon != "" {
+		tags = append(tags, headTag{Tag: "link", Attrs: map[string]string{"rel": "icon", "sizes": "192x192", "href": *config.AndroidIcon}})
+	}
+	if config.BrowserConfigURL != nil && *config.BrowserConfigURL != "" {
+		tags = append(tags, headTag{Tag: "meta", Attrs: map[string]string{"name": "msapplication-config", "content": *config.BrowserConfigURL}})
+	}
+	return tags
+}
+
+func buildStructuredDataTags(seo *model.Seo) []headTag {
+	if seo.StructuredData == nil {
+		return nil
+	}
+	var blocks []interface{}
+	sd := seo.StructuredData
+	appendSchema(&blocks, "Organization", sd.Organization)
+	appendSchema(&blocks, "WebSite", sd.Website)
+	appendSchema(&blocks, "BreadcrumbList", sd.BreadcrumbList)
+	appendSchema(&blocks, "Article", sd.Article)
+	appendSchema(&blocks, "Product", sd.Product)
+	appendSchema(&blocks, "LocalBusiness", sd.LocalBusiness)
+	appendSchema(&blocks, "Person", sd.Person)
+	appendSchema(&blocks, "FAQPage", sd.FaqPage)
+	appendSchema(&blocks, "VideoObject", sd.VideoObject)
+	appendSchema(&blocks, "Review", sd.Review)
+
+	var tags []headTag
+	for _, block := range blocks {
+		content, err := jsonLDString(block)
+		if err != nil {
+			continue
+		}
+		tags = append(tags, headTag{Tag: "script", Attrs: map[string]string{"type": "application/ld+json"}, Content: content})
+	}
+	return tags
+}
+
+func metaTag(name, content string) headTag {
+	return headTag{Tag: "meta", Attrs: map[string]string{"name": name, "content": content}}
+}
+
+func propTag(property, content string) headTag {
+	return headTag{Tag: "meta", Attrs: map[string]string{"property": property, "content": content}}
+}
+
+func linkTag(rel, href, hreflang string) headTag {
+	attrs := map[string]string{"rel": rel, "href": href}
+	if hreflang != "" {
+		attrs["hreflang"] = hreflang
+	}
+	return headTag{Tag: "link", Attrs: attrs}
+}
+
+func headTemplateOverrides(configDoc bson.M) map[string]string {
+	if configDoc == nil {
+		return nil
+	}
+	config := getMap(configDoc, "config")
+	raw := getMap(config, "headTemplates")
+	if raw == nil {
+		return nil
+	}
+	out := make(map[string]string, len(raw))
+	for family, v := range raw {
+		if s, ok := v.(string); ok && s != "" {
+			out[family] = s
+		}
+	}
+	return out
+}
+
+func applyHeadTemplateOverrides(tags []headTag, overrides map[string]string) []headTag {
+	if len(overrides) == 0 {
+		return tags
+	}
+	if override, ok := overrides["all"]; ok {
+		return []headTag{{Tag: "raw", Content: override}}
+	}
+	return tags
+}
+
+func renderTagsToHTML(tags []headTag) string {
+	var b strings.Builder
+	for _, t := range tags {
+		b.WriteString(renderTagToHTML(t))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func renderTagToHTML(t headTag) string {
+	if t.Tag == "raw" {
+		return t.Content
+	}
+	if t.Tag == "title" {
+		return fmt.Sprintf("<title>%s</title>", html.EscapeString(t.Content))
+	}
+
+	var b strings.Builder
+	b.WriteString("<")
+	b.WriteString(t.Tag)
+	for _, key := range sortedKeys(t.Attrs) {
+		b.WriteString(fmt.Sprintf(" %s=%q", key, html.EscapeString(t.Attrs[key])))
+	}
+
+	if t.Tag == "script" {
+		b.WriteString(">")
+		b.WriteString(t.Content)
+		b.WriteString("</script>")
+		return b.String()
+	}
+
+	if t.Content != "" {
+		b.WriteString(">")
+		b.WriteString(html.EscapeString(t.Content))
+		b.WriteString("</")
+		b.WriteString(t.Tag)
+		b.WriteString(">")
+		return b.String()
+	}
+
+	b.WriteString(" />")
+	return b.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	// Keep a stable, predictable attribute order for diffable output.
+	preferred := []string{"name", "property", "rel", "href", "hreflang", "http-equiv", "content", "type"}
+	ordered := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, p := range preferred {
+		if _, ok := m[p]; ok {
+			ordered = append(ordered, p)
+			seen[p] = true
+		}
+	}
+	for _, k := range keys {
+		if !seen[k] {
+			ordered = append(ordered, k)
+		}
+	}
+	return ordered
+}
+
+func toModelTags(tags []headTag) []*model.SeoHeadTag {
+	result := make([]*model.SeoHeadTag, 0, len(tags))
+	for _, t := range tags {
+		result = append(result, &model.SeoHeadTag{
+			Tag:     t.Tag,
+			Attrs:   t.Attrs,
+			Content: t.Content,
+		})
+	}
+	return result
+}
+
+// appendSchema records a non-nil Schema.org sub-schema so it can be emitted
+// as its own JSON-LD <script> block, keyed by @type.
+func appendSchema(blocks *[]interface{}, schemaType string, schema interface{}) {
+	if schema == nil {
+		return
+	}
+	if v := reflect.ValueOf(schema); v.Kind() == reflect.Ptr && v.IsNil() {
+		return
+	}
+	*blocks = append(*blocks, namedSchema{Type: schemaType, Value: schema})
+}
+
+type namedSchema struct {
+	Type  string
+	Value interface{}
+}
+
+// jsonLDString marshals a sub-schema to a JSON-LD object carrying @context
+// and @type alongside its own fields.
+func jsonLDString(block interface{}) (string, error) {
+	ns, ok := block.(namedSchema)
+	if !ok {
+		return "", fmt.Errorf("unsupported JSON-LD block type %T", block)
+	}
+
+	raw, err := json.Marshal(ns.Value)
+	if err != nil {
+		return "", err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return "", err
+	}
+	if fields == nil {
+		fields = make(map[string]interface{})
+	}
+	fields["@context"] = "https://schema.org"
+	fields["@type"] = ns.Type
+
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
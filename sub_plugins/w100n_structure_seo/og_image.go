@@ -0,0 +1,365 @@
+package structure_seo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/disintegration/imaging"
+
+	"web100now-clients-platform/app/graph/model"
+	"web100now-clients-platform/core/db/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	ogImageWidth  = 1200
+	ogImageHeight = 630
+)
+
+// ogTemplate mirrors one entry of SeoConfig.config.ogTemplates.
+type ogTemplate struct {
+	Name         string
+	Background   string
+	LogoPosition string
+	TitleFont    string
+	TitleColor   string
+	TitleBox     ogTemplateBox
+	SubtitleBox  ogTemplateBox
+	Pattern      string
+}
+
+type ogTemplateBox struct {
+	X, Y, W, H int
+}
+
+// GenerateOgImage composites a 1200x630 PNG OG image for pageKey using
+// templateName from SeoConfig.config.ogTemplates, stores it on the CDN, and
+// updates the page's openGraph/twitter image fields.
+func (r *Resolver) GenerateOgImage(ctx context.Context, pageKey, templateName string) (*model.SeoOgImageResult, error) {
+	if !localDevelopmentEnabled() {
+		return nil, fmt.Errorf("OG image generation is available only when LOCAL_DEVELOPMENT=true")
+	}
+
+	db, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("DB connect error: %w", err)
+	}
+
+	img, err := renderOgImageForPage(ctx, db, pageKey, templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode OG image: %w", err)
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	hash := hex.EncodeToString(sum[:])[:12]
+
+	clientName := resolveClientName(ctx)
+	dir := filepath.Join("cdn", clientName, "og")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create OG image directory: %w", err)
+	}
+	localPath := filepath.Join(dir, fmt.Sprintf("%s-%s.png", sanitizeClientDirectoryName(pageKey), hash))
+	if err := os.WriteFile(localPath, buf.Bytes(), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write OG image: %w", err)
+	}
+	cdnPath := "/" + filepath.ToSlash(localPath)
+
+	collection := db.Collection("structure_seo")
+	_, err = collection.UpdateOne(ctx, bson.M{"pageKey": pageKey}, bson.M{"$set": bson.M{
+		"openGraph.og:image":        cdnPath,
+		"openGraph.og:image:width":  ogImageWidth,
+		"openGraph.og:image:height": ogImageHeight,
+		"twitterCard.twitter:image": cdnPath,
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist OG image reference: %w", err)
+	}
+
+	return &model.SeoOgImageResult{
+		PageKey: pageKey,
+		CdnPath: cdnPath,
+		Width:   ogImageWidth,
+		Height:  ogImageHeight,
+	}, nil
+}
+
+// PreviewOgImage renders the same OG image as GenerateOgImage but returns it
+// as a base64 PNG instead of touching storage, so editors can iterate on a
+// template before committing to it.
+func (r *Resolver) PreviewOgImage(ctx context.Context, pageKey, templateName string) (string, error) {
+	db, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		return "", fmt.Errorf("DB connect error: %w", err)
+	}
+
+	img, err := renderOgImageForPage(ctx, db, pageKey, templateName)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", fmt.Errorf("failed to encode OG image preview: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func renderOgImageForPage(ctx context.Context, db *mongo.Database, pageKey, templateName string) (image.Image, error) {
+	seoDoc, err := fetchOneSEO(ctx, db, pageKey)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("no SEO document found for pageKey %q", pageKey)
+		}
+		return nil, fmt.Errorf("fetch SEO error: %w", err)
+	}
+
+	configDoc, err := fetchPluginConfig(ctx, db, "structure_seo")
+	if err != nil {
+		return nil, fmt.Errorf("fetch SEO config error: %w", err)
+	}
+	config := getMap(configDoc, "config")
+
+	tmpl, err := findOgTemplate(config, templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	title := getString(seoDoc, "title")
+	subtitle := getString(seoDoc, "metaDescription")
+	logoURL := getString(config, "faviconUrl")
+
+	return renderOgImage(title, subtitle, logoURL, tmpl)
+}
+
+func findOgTemplate(config map[string]interface{}, templateName string) (ogTemplate, error) {
+	for _, raw := range getArray(config, "ogTemplates") {
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if getString(m, "name") != templateName {
+			continue
+		}
+		return ogTemplate{
+			Name:         getString(m, "name"),
+			Background:   defaultStr(getString(m, "background"), "#111827"),
+			LogoPosition: defaultStr(getString(m, "logoPosition"), "bottom-right"),
+			TitleFont:    getString(m, "titleFont"),
+			TitleColor:   defaultStr(getString(m, "titleColor"), "#ffffff"),
+			TitleBox:     parseBox(getMap(m, "titleBox"), ogTemplateBox{X: 80, Y: 120, W: 1040, H: 300}),
+			SubtitleBox:  parseBox(getMap(m, "subtitleBox"), ogTemplateBox{X: 80, Y: 440, W: 1040, H: 120}),
+			Pattern:      getString(m, "pattern"),
+		}, nil
+	}
+	return ogTemplate{}, fmt.Errorf("OG template %q not found in SeoConfig.config.ogTemplates", templateName)
+}
+
+func parseBox(m map[string]interface{}, fallback ogTemplateBox) ogTemplateBox {
+	if m == nil {
+		return fallback
+	}
+	box := fallback
+	if v := getInt(m, "x"); v != nil {
+		box.X = *v
+	}
+	if v := getInt(m, "y"); v != nil {
+		box.Y = *v
+	}
+	if v := getInt(m, "w"); v != nil {
+		box.W = *v
+	}
+	if v := getInt(m, "h"); v != nil {
+		box.H = *v
+	}
+	return box
+}
+
+// renderOgImage paints the background, optional gradient pattern, logo and
+// greedy-wrapped title/subtitle onto a 1200x630 canvas.
+func renderOgImage(title, subtitle, logoURL string, tmpl ogTemplate) (image.Image, error) {
+	canvas := imaging.New(ogImageWidth, ogImageHeight, parseHexColor(tmpl.Background))
+
+	if tmpl.Pattern == "gradient" {
+		applyVerticalGradient(canvas, parseHexColor(tmpl.Background))
+	}
+
+	if logoURL != "" {
+		if logo, err := loadImageFromPathOrURL(logoURL); err == nil {
+			canvas = pasteLogo(canvas, logo, tmpl.LogoPosition)
+		}
+	}
+
+	drawWrappedText(canvas, title, tmpl.TitleBox, parseHexColor(tmpl.TitleColor))
+	drawWrappedText(canvas, subtitle, tmpl.SubtitleBox, parseHexColor(tmpl.TitleColor))
+
+	return canvas, nil
+}
+
+func applyVerticalGradient(canvas *image.NRGBA, base color.NRGBA) {
+	bounds := canvas.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		alpha := uint8((float64(y) / float64(bounds.Dy())) * 120)
+		overlay := color.NRGBA{R: 0, G: 0, B: 0, A: alpha}
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			canvas.Set(x, y, blendOver(base, overlay))
+		}
+	}
+}
+
+func blendOver(base color.NRGBA, overlay color.NRGBA) color.NRGBA {
+	a := float64(overlay.A) / 255.0
+	return color.NRGBA{
+		R: uint8(float64(overlay.R)*a + float64(base.R)*(1-a)),
+		G: uint8(float64(overlay.G)*a + float64(base.G)*(1-a)),
+		B: uint8(float64(overlay.B)*a + float64(base.B)*(1-a)),
+		A: 255,
+	}
+}
+
+func pasteLogo(canvas *image.NRGBA, logo image.Image, position string) *image.NRGBA {
+	const logoSize = 96
+	const margin = 48
+
+	resized := imaging.Fit(logo, logoSize, logoSize, imaging.Lanczos)
+	bounds := canvas.Bounds()
+
+	var pt image.Point
+	switch position {
+	case "top-left":
+		pt = image.Pt(margin, margin)
+	case "top-right":
+		pt = image.Pt(bounds.Dx()-logoSize-margin, margin)
+	case "bottom-left":
+		pt = image.Pt(margin, bounds.Dy()-logoSize-margin)
+	default: // bottom-right
+		pt = image.Pt(bounds.Dx()-logoSize-margin, bounds.Dy()-logoSize-margin)
+	}
+
+	return imaging.Overlay(canvas, resized, pt, 1.0)
+}
+
+// drawWrappedText greedily wraps text into box.W-wide lines using a fixed
+// bitmap face, then scales the rendered text layer down until it fits inside
+// box.H -- a simple stand-in for a variable-size font without needing a real
+// font file on disk.
+func drawWrappedText(canvas *image.NRGBA, text string, box ogTemplateBox, textColor color.NRGBA) {
+	if text == "" {
+		return
+	}
+
+	face := basicfont.Face7x13
+	lineHeight := face.Metrics().Height.Ceil() + 4
+	lines := greedyWrap(text, box.W, face)
+
+	textLayerHeight := lineHeight * len(lines)
+	if textLayerHeight == 0 {
+		return
+	}
+
+	layer := image.NewNRGBA(image.Rect(0, 0, box.W, textLayerHeight))
+	drawer := &font.Drawer{
+		Dst:  layer,
+		Src:  image.NewUniform(textColor),
+		Face: face,
+	}
+	for i, line := range lines {
+		drawer.Dot = fixed.Point26_6{
+			X: fixed.I(0),
+			Y: fixed.I((i + 1) * lineHeight),
+		}
+		drawer.DrawString(line)
+	}
+
+	scale := 1.0
+	if textLayerHeight > box.H {
+		scale = float64(box.H) / float64(textLayerHeight)
+	}
+	scaledHeight := int(float64(textLayerHeight) * scale)
+	if scaledHeight < 1 {
+		scaledHeight = 1
+	}
+	scaledLayer := imaging.Resize(layer, box.W, scaledHeight, imaging.Lanczos)
+
+	draw.Draw(canvas, image.Rect(box.X, box.Y, box.X+box.W, box.Y+scaledHeight), scaledLayer, image.Point{}, draw.Over)
+}
+
+// greedyWrap breaks text into lines no wider than maxWidth pixels, measured
+// with face, adding words one at a time.
+func greedyWrap(text string, maxWidth int, face font.Face) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var lines []string
+	current := words[0]
+	for _, word := range words[1:] {
+		candidate := current + " " + word
+		if measureText(candidate, face) > maxWidth {
+			lines = append(lines, current)
+			current = word
+			continue
+		}
+		current = candidate
+	}
+	lines = append(lines, current)
+	return lines
+}
+
+func measureText(text string, face font.Face) int {
+	return font.MeasureString(face, text).Ceil()
+}
+
+func parseHexColor(hex string) color.NRGBA {
+	hex = strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	if len(hex) != 6 {
+		return color.NRGBA{R: 17, G: 24, B: 39, A: 255}
+	}
+	r, err1 := strconv.ParseUint(hex[0:2], 16, 8)
+	g, err2 := strconv.ParseUint(hex[2:4], 16, 8)
+	b, err3 := strconv.ParseUint(hex[4:6], 16, 8)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return color.NRGBA{R: 17, G: 24, B: 39, A: 255}
+	}
+	return color.NRGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: 255}
+}
+
+func loadImageFromPathOrURL(path string) (image.Image, error) {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		resp, err := http.Get(path)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		img, _, err := image.Decode(resp.Body)
+		return img, err
+	}
+
+	localPath := strings.TrimPrefix(path, "/")
+	return imaging.Open(localPath, imaging.AutoOrientation(true))
+}
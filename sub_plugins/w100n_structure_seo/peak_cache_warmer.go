@@ -0,0 +1,148 @@
+package structure_seo
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"web100now-clients-platform/core/db/utils"
+	"web100now-clients-platform/core/logger"
+)
+
+// peakCounter is a peakWindow counts-map entry: the rolling hit count plus
+// the clientID seen when the entry was (re)created, so warmDueClients can
+// scope the rewarm lookup to the client that actually peaked instead of the
+// ambient background context, which carries no ClientData.
+type peakCounter struct {
+	count    int64
+	clientID string
+}
+
+// peakWindow tracks a rolling per-client request count over a fixed
+// duration, resetting a client's counter once the window has elapsed since
+// its first sample. Modeled on the peakRequest30/peakRequest60 pattern used
+// elsewhere to detect which tenants are heading into a traffic peak.
+type peakWindow struct {
+	duration    time.Duration
+	counts      sync.Map // clientName -> *peakCounter
+	windowStart sync.Map // clientName -> time.Time
+}
+
+func newPeakWindow(d time.Duration) *peakWindow {
+	return &peakWindow{duration: d}
+}
+
+func (w *peakWindow) sample(clientName, clientID string) {
+	now := time.Now()
+	startAny, loaded := w.windowStart.LoadOrStore(clientName, now)
+	if loaded {
+		if start := startAny.(time.Time); now.Sub(start) > w.duration {
+			w.windowStart.Store(clientName, now)
+			w.counts.Store(clientName, &peakCounter{clientID: clientID})
+		}
+	}
+
+	counterAny, _ := w.counts.LoadOrStore(clientName, &peakCounter{clientID: clientID})
+	atomic.AddInt64(&counterAny.(*peakCounter).count, 1)
+}
+
+// peakClient identifies a client whose request count crossed a threshold:
+// Name is the sanitized directory-safe name (for logging), ID is the
+// clientId to scope the rewarm's config lookup by.
+type peakClient struct {
+	Name string
+	ID   string
+}
+
+// above returns the clients whose current-window count meets or exceeds
+// threshold.
+func (w *peakWindow) above(threshold int64) []peakClient {
+	var clients []peakClient
+	w.counts.Range(func(key, value interface{}) bool {
+		counter := value.(*peakCounter)
+		if atomic.LoadInt64(&counter.count) >= threshold {
+			clients = append(clients, peakClient{Name: key.(string), ID: counter.clientID})
+		}
+		return true
+	})
+	return clients
+}
+
+var (
+	peakRequests30 = newPeakWindow(30 * time.Minute)
+	peakRequests60 = newPeakWindow(60 * time.Minute)
+)
+
+// RegisterPeakSample records a hit against clientName/key so the cache
+// warmer can learn which client SEO/plugin configs are requested most
+// frequently. clientID is recorded alongside clientName so a later rewarm
+// can scope its config lookup to this client. The Seo resolver calls this on
+// every page read; other SEO/plugin reads (SeoConfig and the like) are not
+// sampled.
+func RegisterPeakSample(clientName, clientID, key string) {
+	peakRequests30.sample(clientName, clientID)
+	peakRequests60.sample(clientName, clientID)
+}
+
+// StartPeakCacheWarmer runs until ctx is canceled, waking every tick to
+// re-warm the favicon zip (via warmClientCache) for any client whose
+// 30-minute request count has crossed warmThreshold, so the CDN paths
+// referenced from config["public"] are already warm before a peak window.
+// Structured-data JSON-LD blobs are not regenerated by the warmer.
+func StartPeakCacheWarmer(ctx context.Context, tick time.Duration, warmThreshold int64) {
+	go func() {
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				warmDueClients(ctx, warmThreshold)
+			}
+		}
+	}()
+}
+
+func warmDueClients(ctx context.Context, warmThreshold int64) {
+	for _, client := range peakRequests30.above(warmThreshold) {
+		if err := warmClientCache(ctx, client); err != nil {
+			logger.LogError(fmt.Sprintf("[PeakCacheWarmer] failed to warm client %s", client.Name), err)
+		}
+	}
+}
+
+// warmClientCache re-zips the client's existing favicon bundle directory so
+// the CDN-served archive reflects any asset changes since it was last built.
+// It works off assets already on disk; it does not require a fresh upload.
+//
+// The config lookup is scoped by client.ID rather than resolveClientID(ctx):
+// ctx here is StartPeakCacheWarmer's long-lived background context, which
+// carries no middleware.ClientData, so resolveClientID(ctx) would always
+// resolve to "" regardless of which client actually peaked.
+func warmClientCache(ctx context.Context, client peakClient) error {
+	db, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		return fmt.Errorf("DB connect error: %w", err)
+	}
+
+	doc, err := fetchPluginConfigForClient(ctx, db, "structure_seo", client.ID)
+	if err != nil {
+		return fmt.Errorf("fetch SEO config error: %w", err)
+	}
+
+	config := getMap(doc, "config")
+	zipInfo := getMap(config, "faviconsZip")
+	zipPath := getString(zipInfo, "path")
+	if zipPath == "" {
+		return nil
+	}
+
+	logger.LogInfo(fmt.Sprintf("[PeakCacheWarmer] warming favicon cache for client %s", client.Name))
+	zipCtx, cancel := context.WithTimeout(ctx, defaultFaviconStageDeadlines.ZipWalk)
+	defer cancel()
+	return createZipArchiveWithContext(zipCtx, zipPath, filepath.Dir(zipPath))
+}
@@ -39,6 +39,8 @@ func NewResolver() *Resolver {
 
 // Seo returns SEO data for the specified page (pageKey).
 func (r *Resolver) Seo(ctx context.Context, pageKey string) (*model.Seo, error) {
+	RegisterPeakSample(resolveClientName(ctx), resolveClientID(ctx), pageKey)
+
 	db, err := utils.GetMongoDB(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("DB connect error: %w", err)
@@ -113,11 +115,22 @@ func (r *Resolver) UpdateSeoConfig(ctx context.Context, payload core.JSON) (core
 
 	update := bson.M{}
 	if payload != nil {
-		update["config"] = map[string]interface{}(payload)
+		configUpdate := map[string]interface{}(payload)
+
+		if rotate, _ := configUpdate["rotateIndexNowKey"].(bool); rotate {
+			delete(configUpdate, "rotateIndexNowKey")
+			newKey, err := rotateIndexNowKey(resolveClientName(ctx))
+			if err != nil {
+				return nil, fmt.Errorf("failed to rotate IndexNow key: %w", err)
+			}
+			configUpdate["indexNowKey"] = newKey
+		}
+
+		update["config"] = configUpdate
 	}
 
 	collection := db.Collection("plugins")
-	filter := bson.M{"short_name": "structure_seo"}
+	filter := pluginFilter(ctx, "structure_seo")
 
 	if len(update) > 0 {
 		if _, err := collection.UpdateOne(ctx, filter, bson.M{"$set": update}); err != nil {
@@ -138,7 +151,17 @@ func (r *Resolver) UpdateSeoConfig(ctx context.Context, payload core.JSON) (core
 	return jsonDoc, nil
 }
 
-// GenerateSeoFavicons processes an uploaded image and generates favicon assets and Next.js-ready bundle.
+// GenerateSeoFavicons processes an uploaded image and generates favicon
+// assets and a Next.js-ready bundle: PNG targets, SVG passthrough +
+// monochrome pinned-tab, maskable Android icons, Windows tiles +
+// browserconfig.xml, favicon.ico, and site.webmanifest.
+//
+// WebP/AVIF variants are NOT generated. Go's standard library and this
+// repo's existing image dependency (github.com/disintegration/imaging) have
+// no AVIF encoder and no pure-Go WebP encoder; adding real next-gen-format
+// support needs a vendored encoder dependency this package doesn't carry
+// today, not another stub. Don't silently reintroduce WebP/AVIF fields
+// without one.
 func (r *Resolver) GenerateSeoFavicons(ctx context.Context, file graphql.Upload) (*model.SeoFaviconPackage, error) {
 	if !localDevelopmentEnabled() {
 		return nil, errors.New("favicon generation is allowed only when LOCAL_DEVELOPMENT=true")
@@ -153,21 +176,41 @@ func (r *Resolver) GenerateSeoFavicons(ctx context.Context, file graphql.Upload)
 		return nil, fmt.Errorf("DB connect error: %w", err)
 	}
 
+	pipeline := newFaviconPipeline(ctx, defaultFaviconStageDeadlines)
+
 	clientName := resolveClientName(ctx)
+	clientDir := clientName
+	if clientID := resolveClientID(ctx); clientID != "" {
+		clientDir = clientID
+	}
 	tempPath, err := saveUploadToTemp(&file, "seo-favicon")
 	if err != nil {
 		return nil, fmt.Errorf("failed to save upload: %w", err)
 	}
 	defer os.Remove(tempPath)
 
-	srcImage, err := imaging.Open(tempPath, imaging.AutoOrientation(true))
-	if err != nil {
-		return nil, fmt.Errorf("failed to open uploaded image: %w", err)
+	var srcImage image.Image
+	if stageErr := pipeline.stage(pipeline.deadlines.Decode, func(ctx context.Context) error {
+		img, err := imaging.Open(tempPath, imaging.AutoOrientation(true))
+		if err != nil {
+			return err
+		}
+		srcImage = img
+		return nil
+	}); stageErr != nil {
+		if stageErr == context.Canceled || stageErr == context.DeadlineExceeded {
+			return nil, fmt.Errorf("image decode canceled: %w", stageErr)
+		}
+		return nil, fmt.Errorf("failed to open uploaded image: %w", stageErr)
 	}
 
 	timestamp := time.Now().UTC()
 	folderName := timestamp.Format("20060102-150405")
-	baseDir := filepath.Join("cdn", clientName, "favicons", folderName)
+	// Scope the CDN path by the unique clientId, same as the Mongo lookup
+	// below, since the sanitized clientName alone is a collision-prone
+	// filesystem hint and two clients with similar names would otherwise
+	// overwrite each other's favicon assets.
+	baseDir := filepath.Join("cdn", clientDir, "favicons", folderName)
 	if err := os.MkdirAll(baseDir, 0o755); err != nil {
 		return nil, fmt.Errorf("failed to create target directory: %w", err)
 	}
@@ -238,19 +281,62 @@ func (r *Resolver) GenerateSeoFavicons(ctx context.Context, file graphql.Upload)
 		{512, "android-chrome-512x512.png", "android"},
 	}
 
-	for _, target := range pngTargets {
-		resized := imaging.Fill(srcImage, target.Size, target.Size, imaging.Center, imaging.Lanczos)
-		targetPath := filepath.Join(baseDir, target.Name)
-		if err := imaging.Save(resized, targetPath, imaging.PNGCompressionLevel(png.BestCompression)); err != nil {
-			return nil, fmt.Errorf("failed to save %s: %w", target.Name, err)
+	if stageErr := pipeline.stage(pipeline.deadlines.Resize, func(ctx context.Context) error {
+		for _, target := range pngTargets {
+			resized := imaging.Fill(srcImage, target.Size, target.Size, imaging.Center, imaging.Lanczos)
+			targetPath := filepath.Join(baseDir, target.Name)
+			if err := imaging.Save(resized, targetPath, imaging.PNGCompressionLevel(png.BestCompression)); err != nil {
+				return fmt.Errorf("failed to save %s: %w", target.Name, err)
+			}
+			sizeLabel := fmt.Sprintf("%dx%d", target.Size, target.Size)
+			addAsset(target.Name, target.Purpose, "image/png", &sizeLabel, targetPath)
+		}
+		return nil
+	}); stageErr != nil {
+		if stageErr == context.Canceled || stageErr == context.DeadlineExceeded {
+			return nil, fmt.Errorf("favicon resize canceled: %w", stageErr)
+		}
+		return nil, stageErr
+	}
+
+	// SVG uploads additionally get a passthrough favicon.svg plus a monochrome
+	// safari-pinned-tab.svg.
+	if isSVGSource(file.Filename, file.ContentType) {
+		svgPath, pinnedTabPath, err := writeSVGFaviconAssets(tempPath, baseDir)
+		if err != nil {
+			return nil, err
+		}
+		addAsset("favicon.svg", "browser_tab", "image/svg+xml", nil, svgPath)
+		addAsset("safari-pinned-tab.svg", "mask_icon", "image/svg+xml", nil, pinnedTabPath)
+	}
+
+	// Maskable Android icons: padded onto a transparent canvas so the logo
+	// sits within the 80% safe zone Android applies when masking adaptive icons.
+	if stageErr := pipeline.stage(pipeline.deadlines.Resize, func(ctx context.Context) error {
+		for _, size := range []int{192, 512} {
+			name, localPath, err := generateMaskableIcon(srcImage, size, baseDir)
+			if err != nil {
+				return err
+			}
+			sizeLabel := fmt.Sprintf("%dx%d", size, size)
+			addAsset(name, "maskable", "image/png", &sizeLabel, localPath)
+		}
+		return nil
+	}); stageErr != nil {
+		if stageErr == context.Canceled || stageErr == context.DeadlineExceeded {
+			return nil, fmt.Errorf("maskable icon resize canceled: %w", stageErr)
 		}
-		sizeLabel := fmt.Sprintf("%dx%d", target.Size, target.Size)
-		addAsset(target.Name, target.Purpose, "image/png", &sizeLabel, targetPath)
+		return nil, stageErr
 	}
 
 	// Generate favicon.ico with multiple sizes
 	icoPath := filepath.Join(baseDir, "favicon.ico")
-	if err := createICO(srcImage, icoPath); err != nil {
+	if err := pipeline.runICOStage(icoPath, func() error {
+		return createICO(srcImage, icoPath)
+	}); err != nil {
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return nil, fmt.Errorf("favicon.ico generation canceled: %w", err)
+		}
 		return nil, fmt.Errorf("failed to create favicon.ico: %w", err)
 	}
 	addAsset("favicon.ico", "browser_tab", "image/x-icon", nil, icoPath)
@@ -299,36 +385,27 @@ func (r *Resolver) GenerateSeoFavicons(ctx context.Context, file graphql.Upload)
 	manifestStr := string(manifestBytes)
 	addAsset("site.webmanifest", "manifest", "application/manifest+json", nil, manifestPath)
 
-	// Generate browserconfig.xml
-	var tilePath string
-	for _, asset := range assets {
-		if asset.Name == "mstile-150x150.png" {
-			tilePath = asset.CdnPath
-			break
+	// Generate Windows tiles (70, 150, wide 310x150, 310x310) and browserconfig.xml
+	tileCDNPaths := make(map[string]string, len(windowsTileTargets))
+	if stageErr := pipeline.stage(pipeline.deadlines.Resize, func(ctx context.Context) error {
+		for _, target := range windowsTileTargets {
+			tileLocal, err := generateWindowsTile(srcImage, target, baseDir)
+			if err != nil {
+				return err
+			}
+			sizeLabel := fmt.Sprintf("%dx%d", target.Width, target.Height)
+			addAsset(target.Name, "windows_tile", "image/png", &sizeLabel, tileLocal)
+			tileCDNPaths[target.Name] = assets[len(assets)-1].CdnPath
 		}
-	}
-	if tilePath == "" {
-		// create 150x150 tile
-		tileImg := imaging.Fill(srcImage, 150, 150, imaging.Center, imaging.Lanczos)
-		tileLocal := filepath.Join(baseDir, "mstile-150x150.png")
-		if err := imaging.Save(tileImg, tileLocal, imaging.PNGCompressionLevel(png.BestCompression)); err != nil {
-			return nil, fmt.Errorf("failed to create mstile image: %w", err)
+		return nil
+	}); stageErr != nil {
+		if stageErr == context.Canceled || stageErr == context.DeadlineExceeded {
+			return nil, fmt.Errorf("windows tile resize canceled: %w", stageErr)
 		}
-		sizeLabel := "150x150"
-		addAsset("mstile-150x150.png", "windows_tile", "image/png", &sizeLabel, tileLocal)
-		tilePath = assets[len(assets)-1].CdnPath
-	}
-
-	browserConfig := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
-<browserconfig>
-  <msapplication>
-    <tile>
-      <square150x150logo src="%s"/>
-      <TileColor>#ffffff</TileColor>
-    </tile>
-  </msapplication>
-</browserconfig>
-`, tilePath)
+		return nil, stageErr
+	}
+
+	browserConfig := buildBrowserConfigXML(tileCDNPaths)
 	browserConfigPath := filepath.Join(baseDir, "browserconfig.xml")
 	if err := os.WriteFile(browserConfigPath, []byte(browserConfig), 0o644); err != nil {
 		return nil, fmt.Errorf("failed to write browserconfig.xml: %w", err)
@@ -345,14 +422,25 @@ func (r *Resolver) GenerateSeoFavicons(ctx context.Context, file graphql.Upload)
 
 	// Create ZIP archive
 	zipFilePath := filepath.Join(baseDir, "favicons-nextjs.zip")
-	if err := createZipArchive(zipFilePath, baseDir); err != nil {
-		return nil, fmt.Errorf("failed to create ZIP archive: %w", err)
+	zipCtx, cancelZip := context.WithTimeout(ctx, pipeline.deadlines.ZipWalk)
+	zipErr := createZipArchiveWithContext(zipCtx, zipFilePath, baseDir)
+	cancelZip()
+	if zipErr != nil {
+		if zipErr == context.Canceled || zipErr == context.DeadlineExceeded {
+			return nil, fmt.Errorf("ZIP archive generation canceled: %w", zipErr)
+		}
+		return nil, fmt.Errorf("failed to create ZIP archive: %w", zipErr)
 	}
 	zipCdnPath := "/" + filepath.ToSlash(zipFilePath)
 	addAsset("favicons-nextjs.zip", "bundle", "application/zip", nil, zipFilePath)
 
 	// Persist metadata to plugins config.public
-	if err := updatePluginFavicons(ctx, db, assetsForConf, manifestStr, manifestPath, browserConfig, browserConfigPath, zipFilePath, zipCdnPath, file.Filename, instructions, timestamp); err != nil {
+	if err := pipeline.runMongoUpdateStage(func(stageCtx context.Context) error {
+		return updatePluginFavicons(stageCtx, db, assetsForConf, manifestStr, manifestPath, browserConfig, browserConfigPath, zipFilePath, zipCdnPath, file.Filename, instructions, timestamp)
+	}); err != nil {
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return nil, fmt.Errorf("favicon metadata persistence canceled: %w", err)
+		}
 		return nil, fmt.Errorf("failed to persist favicon metadata: %w", err)
 	}
 
@@ -390,15 +478,42 @@ func fetchOneSEO(ctx context.Context, db *mongo.Database, pageKey string) (bson.
 	return doc, err
 }
 
-// fetchPluginConfig reads plugin configuration from the plugins collection by short_name.
+// fetchPluginConfig reads plugin configuration from the plugins collection by
+// short_name, scoped to the requesting client's ClientID when one is present
+// on ctx so clients with colliding sanitized names don't read each other's
+// config.
 func fetchPluginConfig(ctx context.Context, db *mongo.Database, shortName string) (bson.M, error) {
+	return fetchPluginConfigForClient(ctx, db, shortName, resolveClientID(ctx))
+}
+
+// fetchPluginConfigForClient is fetchPluginConfig with the client scope
+// passed explicitly instead of read off ctx, for callers (like the peak
+// cache warmer) running on a background context with no middleware.ClientData.
+func fetchPluginConfigForClient(ctx context.Context, db *mongo.Database, shortName, clientID string) (bson.M, error) {
 	coll := db.Collection("plugins")
-	filter := bson.M{"short_name": shortName}
+	filter := pluginFilterForClient(shortName, clientID)
 	var doc bson.M
 	err := coll.FindOne(ctx, filter).Decode(&doc)
 	return doc, err
 }
 
+// pluginFilter builds the plugins collection lookup filter for shortName,
+// adding a clientId scope whenever resolveClientID(ctx) has one so lookups
+// stop relying solely on the globally-shared short_name.
+func pluginFilter(ctx context.Context, shortName string) bson.M {
+	return pluginFilterForClient(shortName, resolveClientID(ctx))
+}
+
+// pluginFilterForClient is pluginFilter with the clientId passed explicitly
+// instead of resolved from ctx.
+func pluginFilterForClient(shortName, clientID string) bson.M {
+	filter := bson.M{"short_name": shortName}
+	if clientID != "" {
+		filter["clientId"] = clientID
+	}
+	return filter
+}
+
 // convertConfigToModel transforms plugin config bson.M → *model.SeoConfig.
 func convertConfigToModel(doc bson.M) *model.SeoConfig {
 	oid, _ := doc["_id"].(primitive.ObjectID)
@@ -456,6 +571,9 @@ func convertConfigToModel(doc bson.M) *model.SeoConfig {
 		CookieConsentRequired: getBoolPtr(config, "cookieConsentRequired"),
 		RobotsURL:             optional(getString(config, "robotsUrl")),
 		Webmanifest:           optional(getString(config, "webmanifest")),
+		AppleTouchIcon:        optional(getString(config, "appleTouchIcon")),
+		AndroidIcon:           optional(getString(config, "androidIcon")),
+		BrowserConfigURL:      optional(getString(config, "browserConfigUrl")),
 		LocalBusiness:         localBusiness,
 	}
 }
@@ -696,27 +814,91 @@ func convertDublinCore(dc map[string]interface{}) *model.DublinCoreMeta {
 	}
 }
 
-// convertStructuredData converts structured data
+// convertStructuredData converts structured data. Each Schema.org type is
+// resolved through the schema registry first (see schema_registry.go) so
+// third-party Structure plugins can override a conversion or register types
+// this package doesn't know about; the hard-coded convert*Schema functions
+// below are the fallback used when nothing is registered for a type.
 func convertStructuredData(sd map[string]interface{}) *model.StructuredData {
 	if sd == nil {
 		return nil
 	}
 
 	return &model.StructuredData{
-		JSONLd:         optional(getString(sd, "jsonLd")),
-		Organization:   convertOrganizationSchema(getMap(sd, "organization")),
-		Website:        convertWebsiteSchema(getMap(sd, "website")),
-		BreadcrumbList: convertBreadcrumbList(getMap(sd, "breadcrumbList")),
-		Article:        convertArticleSchema(getMap(sd, "article")),
-		Product:        convertProductSchema(getMap(sd, "product")),
-		LocalBusiness:  convertLocalBusinessSchema(getMap(sd, "localBusiness")),
-		Person:         convertPersonSchema(getMap(sd, "person")),
-		FaqPage:        convertFAQPageSchema(getMap(sd, "faqPage")),
-		VideoObject:    convertVideoObjectSchema(getMap(sd, "videoObject")),
-		Review:         convertReviewSchema(getMap(sd, "review")),
+		JSONLd: mergedJSONLd(sd),
+		Organization: asOrganizationSchema(resolveSchemaConverter("Organization", getMap(sd, "organization"),
+			func(m map[string]interface{}) interface{} { return convertOrganizationSchema(m) })),
+		Website: asWebsiteSchema(resolveSchemaConverter("WebSite", getMap(sd, "website"),
+			func(m map[string]interface{}) interface{} { return convertWebsiteSchema(m) })),
+		BreadcrumbList: asBreadcrumbListSchema(resolveSchemaConverter("BreadcrumbList", getMap(sd, "breadcrumbList"),
+			func(m map[string]interface{}) interface{} { return convertBreadcrumbList(m) })),
+		Article: asArticleSchema(resolveSchemaConverter("Article", getMap(sd, "article"),
+			func(m map[string]interface{}) interface{} { return convertArticleSchema(m) })),
+		Product: asProductSchema(resolveSchemaConverter("Product", getMap(sd, "product"),
+			func(m map[string]interface{}) interface{} { return convertProductSchema(m) })),
+		LocalBusiness: asLocalBusinessSchema(resolveSchemaConverter("LocalBusiness", getMap(sd, "localBusiness"),
+			func(m map[string]interface{}) interface{} { return convertLocalBusinessSchema(m) })),
+		Person: asPersonSchema(resolveSchemaConverter("Person", getMap(sd, "person"),
+			func(m map[string]interface{}) interface{} { return convertPersonSchema(m) })),
+		FaqPage: asFAQPageSchema(resolveSchemaConverter("FAQPage", getMap(sd, "faqPage"),
+			func(m map[string]interface{}) interface{} { return convertFAQPageSchema(m) })),
+		VideoObject: asVideoObjectSchema(resolveSchemaConverter("VideoObject", getMap(sd, "videoObject"),
+			func(m map[string]interface{}) interface{} { return convertVideoObjectSchema(m) })),
+		Review: asReviewSchema(resolveSchemaConverter("Review", getMap(sd, "review"),
+			func(m map[string]interface{}) interface{} { return convertReviewSchema(m) })),
 	}
 }
 
+func asOrganizationSchema(v interface{}) *model.OrganizationSchema {
+	s, _ := v.(*model.OrganizationSchema)
+	return s
+}
+
+func asWebsiteSchema(v interface{}) *model.WebsiteSchema {
+	s, _ := v.(*model.WebsiteSchema)
+	return s
+}
+
+func asBreadcrumbListSchema(v interface{}) *model.BreadcrumbListSchema {
+	s, _ := v.(*model.BreadcrumbListSchema)
+	return s
+}
+
+func asArticleSchema(v interface{}) *model.ArticleSchema {
+	s, _ := v.(*model.ArticleSchema)
+	return s
+}
+
+func asProductSchema(v interface{}) *model.ProductSchema {
+	s, _ := v.(*model.ProductSchema)
+	return s
+}
+
+func asLocalBusinessSchema(v interface{}) *model.LocalBusinessSchema {
+	s, _ := v.(*model.LocalBusinessSchema)
+	return s
+}
+
+func asPersonSchema(v interface{}) *model.PersonSchema {
+	s, _ := v.(*model.PersonSchema)
+	return s
+}
+
+func asFAQPageSchema(v interface{}) *model.FAQPageSchema {
+	s, _ := v.(*model.FAQPageSchema)
+	return s
+}
+
+func asVideoObjectSchema(v interface{}) *model.VideoObjectSchema {
+	s, _ := v.(*model.VideoObjectSchema)
+	return s
+}
+
+func asReviewSchema(v interface{}) *model.ReviewSchema {
+	s, _ := v.(*model.ReviewSchema)
+	return s
+}
+
 // convertAlternateLanguages converts alternate languages
 func convertAlternateLanguages(al []interface{}) []*model.AlternateLanguage {
 	if al == nil {
@@ -965,6 +1147,19 @@ func convertVideoObjectSchema(video map[string]interface{}) *model.VideoObjectSc
 		return nil
 	}
 
+	// Editors may paste a raw share link into structuredData.videoObject.url
+	// instead of hand-authoring the rest of the VideoObject fields; resolve it
+	// the same way the IngestVideoFromURL resolver does rather than leaving
+	// the editor to call that resolver separately and copy the result back in.
+	// The registry's schema.ConverterFunc signature has no context parameter,
+	// so this uses context.Background() rather than threading ctx through
+	// every registered Schema.org converter for the sake of this one type.
+	if rawURL := getString(video, "url"); rawURL != "" && getString(video, "contentUrl") == "" && getString(video, "embedUrl") == "" {
+		if ingested, err := ingestVideoFromURL(context.Background(), rawURL); err == nil {
+			return ingested
+		}
+	}
+
 	return &model.VideoObjectSchema{
 		Name:         getString(video, "name"),
 		Description:  optional(getString(video, "description")),
@@ -1199,6 +1394,22 @@ func resolveClientName(ctx context.Context) string {
 	return defaultClientDir
 }
 
+// resolveClientID returns the opaque client identifier (ULID/UUID) from the
+// request's ClientData, or "" if none is set. Unlike resolveClientName, this
+// is the actual Mongo lookup key for plugins.short_name scoping: the
+// sanitized client name is only a filesystem-safe directory hint and is not
+// guaranteed unique across clients.
+func resolveClientID(ctx context.Context) string {
+	clientDataAny := ctx.Value(middleware.ClientDataKey)
+	if clientDataAny == nil {
+		return ""
+	}
+	if cd, ok := clientDataAny.(*middleware.ClientData); ok {
+		return cd.ClientID
+	}
+	return ""
+}
+
 func sanitizeClientDirectoryName(name string) string {
 	name = strings.TrimSpace(strings.ToLower(name))
 	if name == "" {
@@ -1256,17 +1467,24 @@ func saveUploadToTemp(upload *graphql.Upload, prefix string) (string, error) {
 	return tmpFile.Name(), nil
 }
 
+var icoSizes = []int{16, 32, 48, 64, 256}
+
 func createICO(src image.Image, targetPath string) error {
-	resized := imaging.Resize(src, 64, 64, imaging.Lanczos)
-	nrgba := image.NewNRGBA(resized.Bounds())
-	draw.Draw(nrgba, nrgba.Bounds(), resized, image.Point{}, draw.Src)
 	file, err := os.Create(targetPath)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	return ico.Encode(file, nrgba)
+	images := make([]image.Image, 0, len(icoSizes))
+	for _, size := range icoSizes {
+		resized := imaging.Resize(src, size, size, imaging.Lanczos)
+		nrgba := image.NewNRGBA(resized.Bounds())
+		draw.Draw(nrgba, nrgba.Bounds(), resized, image.Point{}, draw.Src)
+		images = append(images, nrgba)
+	}
+
+	return ico.Encode(file, images...)
 }
 
 func createZipArchive(zipPath, baseDir string) error {
@@ -1382,7 +1600,7 @@ func updatePluginFavicons(
 	config["lastFaviconGeneratedAt"] = generatedAt.Format(time.RFC3339)
 
 	collection := db.Collection("plugins")
-	_, err = collection.UpdateOne(ctx, bson.M{"short_name": "structure_seo"}, bson.M{
+	_, err = collection.UpdateOne(ctx, pluginFilter(ctx, "structure_seo"), bson.M{
 		"$set": bson.M{"config": config},
 	})
 	return err
@@ -0,0 +1,45 @@
+// Package schema provides a pluggable Schema.org converter registry, so
+// Structure plugins can add support for new @type values (Event, Recipe,
+// Course, JobPosting, HowTo, SoftwareApplication, ...) without editing
+// w100n_structure_seo directly.
+package schema
+
+import "sync"
+
+// ConverterFunc turns a raw structuredData sub-document into the value that
+// should be marshaled into its JSON-LD block.
+type ConverterFunc func(map[string]interface{}) interface{}
+
+var (
+	mu         sync.RWMutex
+	converters = make(map[string]ConverterFunc)
+)
+
+// RegisterConverter associates typeName (a Schema.org @type, e.g.
+// "Organization" or "Event") with fn. Registering the same typeName twice
+// replaces the previous converter, so a plugin can override a built-in
+// default if it needs different behavior.
+func RegisterConverter(typeName string, fn ConverterFunc) {
+	mu.Lock()
+	defer mu.Unlock()
+	converters[typeName] = fn
+}
+
+// Lookup returns the converter registered for typeName, if any.
+func Lookup(typeName string) (ConverterFunc, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	fn, ok := converters[typeName]
+	return fn, ok
+}
+
+// Registered returns the @type names currently registered.
+func Registered() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(converters))
+	for name := range converters {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,115 @@
+package structure_seo
+
+import (
+	"encoding/json"
+	"strings"
+
+	"web100now-clients-platform/app/plugins/w100n_structure_websites/sub_plugins/w100n_structure_seo/schema"
+)
+
+// init registers the built-in Schema.org converters this package already
+// knows how to render, so they go through the same lookup path as converters
+// registered by third-party Structure plugins. A plugin can override any of
+// these by calling schema.RegisterConverter with the same type name.
+func init() {
+	schema.RegisterConverter("Organization", func(m map[string]interface{}) interface{} { return convertOrganizationSchema(m) })
+	schema.RegisterConverter("WebSite", func(m map[string]interface{}) interface{} { return convertWebsiteSchema(m) })
+	schema.RegisterConverter("BreadcrumbList", func(m map[string]interface{}) interface{} { return convertBreadcrumbList(m) })
+	schema.RegisterConverter("Article", func(m map[string]interface{}) interface{} { return convertArticleSchema(m) })
+	schema.RegisterConverter("Product", func(m map[string]interface{}) interface{} { return convertProductSchema(m) })
+	schema.RegisterConverter("LocalBusiness", func(m map[string]interface{}) interface{} { return convertLocalBusinessSchema(m) })
+	schema.RegisterConverter("Person", func(m map[string]interface{}) interface{} { return convertPersonSchema(m) })
+	schema.RegisterConverter("FAQPage", func(m map[string]interface{}) interface{} { return convertFAQPageSchema(m) })
+	schema.RegisterConverter("VideoObject", func(m map[string]interface{}) interface{} { return convertVideoObjectSchema(m) })
+	schema.RegisterConverter("Review", func(m map[string]interface{}) interface{} { return convertReviewSchema(m) })
+}
+
+// resolveSchemaConverter looks typeName up in the schema registry and, if
+// found, runs it against raw; otherwise it falls back to the hard-coded
+// convert*Schema function passed in by the caller. fallback is always
+// registered by the init() above, so in practice this only diverges when a
+// plugin overrides the entry at runtime.
+func resolveSchemaConverter(typeName string, raw map[string]interface{}, fallback func(map[string]interface{}) interface{}) interface{} {
+	if raw == nil {
+		return nil
+	}
+	if fn, ok := schema.Lookup(typeName); ok {
+		return fn(raw)
+	}
+	return fallback(raw)
+}
+
+// schemaExtensions converts any additional Schema.org types present under
+// sd["extensions"] (a map of @type name -> raw sub-document) through the
+// registry. model.StructuredData has no dedicated field for these, since its
+// schema is fixed by the GraphQL model, so their JSON-LD is folded into the
+// existing JSONLd passthrough string rather than dropped.
+func schemaExtensions(sd map[string]interface{}) []string {
+	extensions := getMap(sd, "extensions")
+	if extensions == nil {
+		return nil
+	}
+
+	var blocks []string
+	for typeName, raw := range extensions {
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fn, ok := schema.Lookup(typeName)
+		if !ok {
+			continue
+		}
+		value := fn(m)
+		if value == nil {
+			continue
+		}
+
+		ld := map[string]interface{}{"@context": "https://schema.org", "@type": typeName}
+		if asMap, ok := toJSONMap(value); ok {
+			for k, v := range asMap {
+				ld[k] = v
+			}
+		}
+		encoded, err := json.Marshal(ld)
+		if err != nil {
+			continue
+		}
+		blocks = append(blocks, string(encoded))
+	}
+	return blocks
+}
+
+// mergedJSONLd returns sd["jsonLd"] with any registry-resolved extension
+// blocks appended, so callers that only know about the passthrough field
+// still see plugin-contributed Schema.org types.
+func mergedJSONLd(sd map[string]interface{}) *string {
+	base := getString(sd, "jsonLd")
+	extensions := schemaExtensions(sd)
+	if len(extensions) == 0 {
+		return optional(base)
+	}
+
+	parts := make([]string, 0, len(extensions)+1)
+	if base != "" {
+		parts = append(parts, base)
+	}
+	parts = append(parts, extensions...)
+	merged := strings.Join(parts, "\n")
+	return &merged
+}
+
+// toJSONMap round-trips value through encoding/json to get a plain
+// map[string]interface{}, used so extension converters can return any struct
+// shape and still merge cleanly with the @context/@type envelope above.
+func toJSONMap(value interface{}) (map[string]interface{}, bool) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(encoded, &m); err != nil {
+		return nil, false
+	}
+	return m, true
+}
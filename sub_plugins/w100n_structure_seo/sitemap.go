@@ -0,0 +1,292 @@
+package structure_seo
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"web100now-clients-platform/core/db/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const indexNowEndpoint = "https://api.indexnow.org/indexnow"
+
+// sitemapURLSet / sitemapURL mirror the sitemaps.org schema, including the
+// xhtml:link alternate-language extension used for hreflang annotations.
+type sitemapURLSet struct {
+	XMLName    xml.Name     `xml:"urlset"`
+	Xmlns      string       `xml:"xmlns,attr"`
+	XmlnsXhtml string       `xml:"xmlns:xhtml,attr"`
+	URLs       []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc             string             `xml:"loc"`
+	LastModified    string             `xml:"lastmod,omitempty"`
+	ChangeFrequency string             `xml:"changefreq,omitempty"`
+	Priority        string             `xml:"priority,omitempty"`
+	Alternates      []sitemapXhtmlLink `xml:"xhtml:link"`
+}
+
+type sitemapXhtmlLink struct {
+	Rel      string `xml:"rel,attr"`
+	Hreflang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
+}
+
+// ExportSeoSitemap walks the structure_seo collection and renders a valid
+// sitemap.xml, including alternate-language links for pages that carry
+// AlternateLanguages.
+func (r *Resolver) ExportSeoSitemap(ctx context.Context) (string, error) {
+	db, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		return "", fmt.Errorf("DB connect error: %w", err)
+	}
+
+	cursor, err := db.Collection("structure_seo").Find(ctx, bson.M{})
+	if err != nil {
+		return "", fmt.Errorf("failed to query structure_seo: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	set := sitemapURLSet{Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9", XmlnsXhtml: "http://www.w3.org/1999/xhtml"}
+
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return "", fmt.Errorf("failed to decode structure_seo document: %w", err)
+		}
+
+		loc := getString(doc, "canonical")
+		if loc == "" {
+			loc = getString(doc, "page")
+		}
+		if loc == "" {
+			continue
+		}
+
+		entry := sitemapURL{
+			Loc:             loc,
+			LastModified:    getString(doc, "lastModified"),
+			ChangeFrequency: getString(doc, "changeFrequency"),
+		}
+		if priority := getFloat(doc, "priority"); priority != nil {
+			entry.Priority = fmt.Sprintf("%.1f", *priority)
+		}
+
+		for _, alt := range getArray(doc, "alternateLanguages") {
+			if m, ok := alt.(map[string]interface{}); ok {
+				entry.Alternates = append(entry.Alternates, sitemapXhtmlLink{
+					Rel:      "alternate",
+					Hreflang: getString(m, "hreflang"),
+					Href:     getString(m, "href"),
+				})
+			}
+		}
+
+		set.URLs = append(set.URLs, entry)
+	}
+	if err := cursor.Err(); err != nil {
+		return "", fmt.Errorf("cursor error while building sitemap: %w", err)
+	}
+
+	out, err := xml.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal sitemap: %w", err)
+	}
+
+	return xml.Header + string(out), nil
+}
+
+// ImportSeoFromSitemap parses an external sitemap.xml and upserts one
+// structure_seo document per <url> entry, keyed by the URL path, so teams
+// can seed the collection from a legacy site.
+func (r *Resolver) ImportSeoFromSitemap(ctx context.Context, sitemapXML string) (int, error) {
+	if !localDevelopmentEnabled() {
+		return 0, fmt.Errorf("sitemap import is available only when LOCAL_DEVELOPMENT=true")
+	}
+
+	var set sitemapURLSet
+	if err := xml.Unmarshal([]byte(sitemapXML), &set); err != nil {
+		return 0, fmt.Errorf("failed to parse sitemap XML: %w", err)
+	}
+
+	db, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("DB connect error: %w", err)
+	}
+	collection := db.Collection("structure_seo")
+
+	imported := 0
+	for _, entry := range set.URLs {
+		pageKey := pageKeyFromURL(entry.Loc)
+		if pageKey == "" {
+			continue
+		}
+
+		update := bson.M{
+			"pageKey":   pageKey,
+			"page":      pageKey,
+			"canonical": entry.Loc,
+		}
+		if entry.LastModified != "" {
+			update["lastModified"] = entry.LastModified
+		}
+		if entry.ChangeFrequency != "" {
+			update["changeFrequency"] = entry.ChangeFrequency
+		}
+		if entry.Priority != "" {
+			update["priority"] = entry.Priority
+		}
+		if len(entry.Alternates) > 0 {
+			alternates := make([]map[string]interface{}, 0, len(entry.Alternates))
+			for _, a := range entry.Alternates {
+				alternates = append(alternates, map[string]interface{}{"hreflang": a.Hreflang, "href": a.Href})
+			}
+			update["alternateLanguages"] = alternates
+		}
+
+		_, err := collection.UpdateOne(
+			ctx,
+			bson.M{"pageKey": pageKey},
+			bson.M{"$set": update},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return imported, fmt.Errorf("failed to upsert page %q: %w", pageKey, err)
+		}
+		imported++
+	}
+
+	return imported, nil
+}
+
+// pageKeyFromURL derives a stable pageKey from a sitemap location URL by
+// stripping the scheme/host and any trailing slash.
+func pageKeyFromURL(loc string) string {
+	key := loc
+	if idx := strings.Index(key, "://"); idx != -1 {
+		key = key[idx+3:]
+	}
+	if idx := strings.Index(key, "/"); idx != -1 {
+		key = key[idx:]
+	} else {
+		key = "/"
+	}
+	key = strings.TrimSuffix(key, "/")
+	if key == "" {
+		key = "/"
+	}
+	return key
+}
+
+// PingIndexNow submits the given URLs to the IndexNow API using the host key
+// stored in SeoConfig.config.indexNowKey, and ensures the key verification
+// file is published on the CDN before submitting.
+func (r *Resolver) PingIndexNow(ctx context.Context, urls []string) (bool, error) {
+	if len(urls) == 0 {
+		return false, fmt.Errorf("urls cannot be empty")
+	}
+
+	db, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		return false, fmt.Errorf("DB connect error: %w", err)
+	}
+
+	configDoc, err := fetchPluginConfig(ctx, db, "structure_seo")
+	if err != nil {
+		return false, fmt.Errorf("fetch SEO config error: %w", err)
+	}
+	config := getMap(configDoc, "config")
+	key := getString(config, "indexNowKey")
+	if key == "" {
+		return false, fmt.Errorf("SeoConfig.config.indexNowKey is not set")
+	}
+
+	host, err := indexNowHost(urls[0])
+	if err != nil {
+		return false, err
+	}
+
+	clientName := resolveClientName(ctx)
+	if err := publishIndexNowKeyFile(clientName, key); err != nil {
+		return false, fmt.Errorf("failed to publish IndexNow key file: %w", err)
+	}
+
+	body := map[string]interface{}{
+		"host":        host,
+		"key":         key,
+		"keyLocation": fmt.Sprintf("https://%s/%s.txt", host, key),
+		"urlList":     urls,
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal IndexNow payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, indexNowEndpoint, bytes.NewReader(payload))
+	if err != nil {
+		return false, fmt.Errorf("failed to build IndexNow request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("IndexNow request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return false, fmt.Errorf("IndexNow responded with status %d", resp.StatusCode)
+	}
+
+	return true, nil
+}
+
+func indexNowHost(rawURL string) (string, error) {
+	u := rawURL
+	if idx := strings.Index(u, "://"); idx != -1 {
+		u = u[idx+3:]
+	}
+	if idx := strings.Index(u, "/"); idx != -1 {
+		u = u[:idx]
+	}
+	if u == "" {
+		return "", fmt.Errorf("could not determine host from URL %q", rawURL)
+	}
+	return u, nil
+}
+
+func publishIndexNowKeyFile(clientName, key string) error {
+	dir := filepath.Join("cdn", clientName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, key+".txt")
+	return os.WriteFile(path, []byte(key), 0o644)
+}
+
+// rotateIndexNowKey generates a fresh IndexNow key, republishes the key file
+// for clientName, and returns the new key so the caller can persist it on
+// SeoConfig.config.indexNowKey.
+func rotateIndexNowKey(clientName string) (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate IndexNow key: %w", err)
+	}
+	key := hex.EncodeToString(raw)
+	if err := publishIndexNowKeyFile(clientName, key); err != nil {
+		return "", err
+	}
+	return key, nil
+}
@@ -0,0 +1,358 @@
+package structure_seo
+
+import (
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"unicode/utf8"
+
+	"web100now-clients-platform/app/graph/model"
+	"web100now-clients-platform/core/db/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// schemaRule describes the required and recommended properties for one
+// Schema.org type, used by ValidateSeo to produce a Rich Results preflight.
+type schemaRule struct {
+	Required    []string
+	Recommended []string
+}
+
+// schemaRules is a small, self-contained conformance map covering the
+// sub-schemas already handled by convertStructuredData. Property paths use
+// dot notation for nested fields (e.g. "offers.price").
+var schemaRules = map[string]schemaRule{
+	"organization": {
+		Required:    []string{"name"},
+		Recommended: []string{"logo", "url", "sameAs"},
+	},
+	"localBusiness": {
+		Required:    []string{"name", "address", "telephone"},
+		Recommended: []string{"image", "priceRange", "aggregateRating"},
+	},
+	"article": {
+		Required:    []string{"headline", "author", "datePublished"},
+		Recommended: []string{"image", "dateModified", "publisher"},
+	},
+	"product": {
+		Required:    []string{"name"},
+		Recommended: []string{"image", "offers.price", "offers.priceCurrency", "aggregateRating", "review"},
+	},
+	"person": {
+		Required:    []string{"name"},
+		Recommended: []string{"url", "image", "jobTitle"},
+	},
+	"breadcrumbList": {
+		Required:    []string{"itemListElement"},
+		Recommended: nil,
+	},
+	"faqPage": {
+		Required:    []string{"mainEntity"},
+		Recommended: nil,
+	},
+}
+
+const (
+	severityError = "ERROR"
+	severityWarn  = "WARN"
+	severityInfo  = "INFO"
+)
+
+// ValidateSeo runs the stored StructuredData tree and the core meta fields of
+// a page through a self-contained Schema.org/Rich-Results conformance check,
+// returning both the issue list and a 0-100 traffic-light score.
+func (r *Resolver) ValidateSeo(ctx context.Context, pageKey string) (*model.SeoValidationResult, error) {
+	db, err := utils.GetMongoDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("DB connect error: %w", err)
+	}
+
+	doc, err := fetchOneSEO(ctx, db, pageKey)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("no SEO document found for pageKey %q", pageKey)
+		}
+		return nil, fmt.Errorf("fetch SEO error: %w", err)
+	}
+
+	var issues []*model.SeoValidationIssue
+	issues = append(issues, lintCoreFields(doc)...)
+
+	structuredData := getMap(doc, "structuredData")
+	for schemaType, rule := range schemaRules {
+		schema := getMap(structuredData, schemaType)
+		if schema == nil {
+			continue
+		}
+		issues = append(issues, validateSchema(schemaType, schema, rule)...)
+	}
+
+	issues = append(issues, crossReferenceIssues(structuredData)...)
+
+	if ogImage := getString(getMap(doc, "openGraph"), "og:image"); ogImage != "" {
+		if issue := validateOgImage(ctx, db, ogImage); issue != nil {
+			issues = append(issues, issue)
+		}
+	}
+
+	return &model.SeoValidationResult{
+		PageKey: pageKey,
+		Issues:  issues,
+		Score:   computeScore(issues),
+	}, nil
+}
+
+func validateSchema(schemaType string, schema map[string]interface{}, rule schemaRule) []*model.SeoValidationIssue {
+	var issues []*model.SeoValidationIssue
+	for _, prop := range rule.Required {
+		if !hasSchemaProperty(schema, prop) {
+			issues = append(issues, &model.SeoValidationIssue{
+				Path:     fmt.Sprintf("structuredData.%s.%s", schemaType, prop),
+				Severity: severityError,
+				Code:     "MISSING_REQUIRED_PROPERTY",
+				Message:  fmt.Sprintf("%s is required by %s but missing", prop, schemaType),
+			})
+		}
+	}
+	for _, prop := range rule.Recommended {
+		if !hasSchemaProperty(schema, prop) {
+			issues = append(issues, &model.SeoValidationIssue{
+				Path:     fmt.Sprintf("structuredData.%s.%s", schemaType, prop),
+				Severity: severityWarn,
+				Code:     "MISSING_RECOMMENDED_PROPERTY",
+				Message:  fmt.Sprintf("%s is recommended for %s", prop, schemaType),
+			})
+		}
+	}
+	return issues
+}
+
+// hasSchemaProperty resolves a dotted property path (e.g. "offers.price")
+// against a structured-data sub-document, treating the first element of a
+// one-item array the same way convertProductSchema does.
+func hasSchemaProperty(schema map[string]interface{}, path string) bool {
+	current := schema
+	segments := splitPath(path)
+	for i, segment := range segments {
+		raw, ok := current[segment]
+		if !ok || raw == nil {
+			return false
+		}
+		if i == len(segments)-1 {
+			if s, ok := raw.(string); ok {
+				return s != ""
+			}
+			return true
+		}
+
+		switch v := raw.(type) {
+		case map[string]interface{}:
+			current = v
+		case []interface{}:
+			if len(v) == 0 {
+				return false
+			}
+			if m, ok := v[0].(map[string]interface{}); ok {
+				current = m
+			} else {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func splitPath(path string) []string {
+	var segments []string
+	start := 0
+	for i := 0; i < len(path); i++ {
+		if path[i] == '.' {
+			segments = append(segments, path[start:i])
+			start = i + 1
+		}
+	}
+	segments = append(segments, path[start:])
+	return segments
+}
+
+// crossReferenceIssues checks invariants that span multiple properties of
+// the same sub-schema rather than a single missing field.
+func crossReferenceIssues(structuredData map[string]interface{}) []*model.SeoValidationIssue {
+	var issues []*model.SeoValidationIssue
+
+	if bc := getMap(structuredData, "breadcrumbList"); bc != nil {
+		items := getArray(bc, "itemListElement")
+		expected := 1
+		for _, item := range items {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			pos := getInt(m, "position")
+			if pos == nil || *pos != expected {
+				issues = append(issues, &model.SeoValidationIssue{
+					Path:     "structuredData.breadcrumbList.itemListElement",
+					Severity: severityError,
+					Code:     "BREADCRUMB_POSITION_GAP",
+					Message:  fmt.Sprintf("expected position %d but found %v", expected, pos),
+				})
+			}
+			expected++
+		}
+	}
+
+	if art := getMap(structuredData, "article"); art != nil {
+		if author := getMap(art, "author"); author != nil {
+			if getString(author, "name") == "" {
+				issues = append(issues, &model.SeoValidationIssue{
+					Path:     "structuredData.article.author",
+					Severity: severityError,
+					Code:     "AUTHOR_NOT_RESOLVABLE",
+					Message:  "Article.author must resolve to a Person or Organization node with a name",
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// lintCoreFields checks the non-schema SEO fields most likely to affect SERP
+// display: title length, meta description length, and (elsewhere) og:image
+// dimensions.
+func lintCoreFields(doc bson.M) []*model.SeoValidationIssue {
+	var issues []*model.SeoValidationIssue
+
+	title := getString(doc, "title")
+	if l := utf8.RuneCountInString(title); l < 30 || l > 60 {
+		issues = append(issues, &model.SeoValidationIssue{
+			Path:     "title",
+			Severity: severityWarn,
+			Code:     "TITLE_LENGTH",
+			Message:  fmt.Sprintf("title is %d characters; recommended range is 30-60", l),
+		})
+	}
+
+	desc := getString(doc, "metaDescription")
+	if l := utf8.RuneCountInString(desc); l < 70 || l > 160 {
+		issues = append(issues, &model.SeoValidationIssue{
+			Path:     "metaDescription",
+			Severity: severityWarn,
+			Code:     "META_DESCRIPTION_LENGTH",
+			Message:  fmt.Sprintf("metaDescription is %d characters; recommended range is 70-160", l),
+		})
+	}
+
+	return issues
+}
+
+// validateOgImage probes the og:image URL's dimensions (caching the result in
+// structure_seo_validations keyed by URL+etag) and flags images smaller than
+// the 1200x630 minimum recommended for link previews.
+func validateOgImage(ctx context.Context, db *mongo.Database, imageURL string) *model.SeoValidationIssue {
+	width, height, err := fetchImageDimensions(ctx, db, imageURL)
+	if err != nil {
+		return &model.SeoValidationIssue{
+			Path:     "openGraph.og:image",
+			Severity: severityInfo,
+			Code:     "OG_IMAGE_UNREACHABLE",
+			Message:  fmt.Sprintf("could not probe og:image: %v", err),
+		}
+	}
+
+	if width < 1200 || height < 630 {
+		return &model.SeoValidationIssue{
+			Path:     "openGraph.og:image",
+			Severity: severityWarn,
+			Code:     "OG_IMAGE_TOO_SMALL",
+			Message:  fmt.Sprintf("og:image is %dx%d; recommended minimum is 1200x630", width, height),
+		}
+	}
+
+	return nil
+}
+
+func fetchImageDimensions(ctx context.Context, db *mongo.Database, imageURL string) (width, height int, err error) {
+	collection := db.Collection("structure_seo_validations")
+
+	headResp, err := http.Head(imageURL)
+	var etag string
+	if err == nil {
+		etag = headResp.Header.Get("ETag")
+		headResp.Body.Close()
+	}
+
+	if etag != "" {
+		var cached bson.M
+		if err := collection.FindOne(ctx, bson.M{"url": imageURL, "etag": etag}).Decode(&cached); err == nil {
+			if w := getInt(cached, "width"); w != nil {
+				if h := getInt(cached, "height"); h != nil {
+					return *w, *h, nil
+				}
+			}
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return 0, 0, fmt.Errorf("unexpected status %d fetching og:image", resp.StatusCode)
+	}
+	if etag == "" {
+		etag = resp.Header.Get("ETag")
+	}
+
+	cfg, _, err := image.DecodeConfig(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to decode og:image: %w", err)
+	}
+
+	if etag != "" {
+		_, _ = collection.UpdateOne(
+			ctx,
+			bson.M{"url": imageURL, "etag": etag},
+			bson.M{"$set": bson.M{"url": imageURL, "etag": etag, "width": cfg.Width, "height": cfg.Height}},
+			options.Update().SetUpsert(true),
+		)
+	}
+
+	return cfg.Width, cfg.Height, nil
+}
+
+// computeScore turns an issue list into a 0-100 traffic-light score: errors
+// weigh more heavily than warnings, and a clean page scores 100.
+func computeScore(issues []*model.SeoValidationIssue) int {
+	score := 100
+	for _, issue := range issues {
+		switch issue.Severity {
+		case severityError:
+			score -= 15
+		case severityWarn:
+			score -= 5
+		case severityInfo:
+			score -= 1
+		}
+	}
+	if score < 0 {
+		score = 0
+	}
+	return score
+}
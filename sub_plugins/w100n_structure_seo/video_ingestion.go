@@ -0,0 +1,176 @@
+package structure_seo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"web100now-clients-platform/app/graph/model"
+)
+
+var (
+	youtubeWatchRe = regexp.MustCompile(`(?:youtube\.com/watch\?v=|youtube\.com/embed/|youtu\.be/)([\w-]{11})`)
+	vimeoRe        = regexp.MustCompile(`vimeo\.com/(?:video/)?(\d+)`)
+	bilibiliBVRe   = regexp.MustCompile(`(BV[0-9A-Za-z]{10})`)
+)
+
+// IngestVideoFromURL resolves a raw video share link (YouTube, Vimeo, or a
+// Bilibili BV id/URL) into a VideoObjectSchema for the GraphQL SEO builder,
+// so editors can paste a share link instead of hand-authoring the
+// VideoObject JSON-LD. Bilibili links fill name, thumbnail, uploadDate,
+// duration, content and embed URLs; YouTube and Vimeo links fill name,
+// thumbnail, content and embed URLs only, leaving uploadDate and duration
+// unset.
+func (r *Resolver) IngestVideoFromURL(ctx context.Context, videoURL string) (*model.VideoObjectSchema, error) {
+	return ingestVideoFromURL(ctx, videoURL)
+}
+
+// ingestVideoFromURL is the resolver-independent implementation, also used
+// by convertVideoObjectSchema when a structuredData.videoObject map carries
+// only a raw share link in its url field (no contentUrl or embedUrl).
+func ingestVideoFromURL(ctx context.Context, videoURL string) (*model.VideoObjectSchema, error) {
+	if id := youtubeWatchRe.FindStringSubmatch(videoURL); id != nil {
+		return ingestYouTube(ctx, id[1])
+	}
+	if id := vimeoRe.FindStringSubmatch(videoURL); id != nil {
+		return ingestVimeo(ctx, id[1])
+	}
+	if id := bilibiliBVRe.FindStringSubmatch(videoURL); id != nil {
+		return ingestBilibili(ctx, id[1])
+	}
+	return nil, fmt.Errorf("unrecognized video URL %q: expected a YouTube, Vimeo, or Bilibili link", videoURL)
+}
+
+type oEmbedResponse struct {
+	Title        string `json:"title"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+}
+
+func ingestYouTube(ctx context.Context, videoID string) (*model.VideoObjectSchema, error) {
+	watchURL := fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
+	oembed, err := fetchOEmbed(ctx, "https://www.youtube.com/oembed", watchURL)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnail := oembed.ThumbnailURL
+	if thumbnail == "" {
+		thumbnail = fmt.Sprintf("https://img.youtube.com/vi/%s/hqdefault.jpg", videoID)
+	}
+
+	return &model.VideoObjectSchema{
+		Name:         oembed.Title,
+		ThumbnailURL: []string{thumbnail},
+		ContentURL:   strPtr(watchURL),
+		EmbedURL:     strPtr(fmt.Sprintf("https://www.youtube.com/embed/%s", videoID)),
+	}, nil
+}
+
+func ingestVimeo(ctx context.Context, videoID string) (*model.VideoObjectSchema, error) {
+	watchURL := fmt.Sprintf("https://vimeo.com/%s", videoID)
+	oembed, err := fetchOEmbed(ctx, "https://vimeo.com/api/oembed.json", watchURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.VideoObjectSchema{
+		Name:         oembed.Title,
+		ThumbnailURL: nonEmptyStrings(oembed.ThumbnailURL),
+		ContentURL:   strPtr(watchURL),
+		EmbedURL:     strPtr(fmt.Sprintf("https://player.vimeo.com/video/%s", videoID)),
+	}, nil
+}
+
+// bilibiliViewResponse is the subset of bilibili's public view API
+// (x/web-interface/view) this module cares about.
+type bilibiliViewResponse struct {
+	Code int `json:"code"`
+	Data struct {
+		Title    string `json:"title"`
+		Pic      string `json:"pic"`
+		Duration int    `json:"duration"`
+		PubDate  int64  `json:"pubdate"`
+	} `json:"data"`
+}
+
+func ingestBilibili(ctx context.Context, bvID string) (*model.VideoObjectSchema, error) {
+	apiURL := fmt.Sprintf("https://api.bilibili.com/x/web-interface/view?bvid=%s", bvID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve bilibili metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var parsed bilibiliViewResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode bilibili response: %w", err)
+	}
+	if parsed.Code != 0 {
+		return nil, fmt.Errorf("bilibili API returned code %d for %s", parsed.Code, bvID)
+	}
+
+	var uploadDate *string
+	if parsed.Data.PubDate > 0 {
+		d := time.Unix(parsed.Data.PubDate, 0).UTC().Format(time.RFC3339)
+		uploadDate = &d
+	}
+
+	return &model.VideoObjectSchema{
+		Name:         parsed.Data.Title,
+		ThumbnailURL: nonEmptyStrings(parsed.Data.Pic),
+		UploadDate:   uploadDate,
+		Duration:     strPtr(fmt.Sprintf("PT%dS", parsed.Data.Duration)),
+		ContentURL:   strPtr(fmt.Sprintf("https://www.bilibili.com/video/%s", bvID)),
+		EmbedURL:     strPtr(fmt.Sprintf("https://player.bilibili.com/player.html?bvid=%s", bvID)),
+	}, nil
+}
+
+func fetchOEmbed(ctx context.Context, endpoint, targetURL string) (*oEmbedResponse, error) {
+	query := url.Values{}
+	query.Set("url", targetURL)
+	query.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve oEmbed metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("oEmbed endpoint responded with status %d", resp.StatusCode)
+	}
+
+	var parsed oEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode oEmbed response: %w", err)
+	}
+	return &parsed, nil
+}
+
+func nonEmptyStrings(values ...string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func strPtr(s string) *string {
+	return &s
+}
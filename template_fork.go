@@ -0,0 +1,90 @@
+package structure_websites
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"w100n_backend_core/core/db"
+)
+
+// storedTemplate mirrors the shape ApplyStructureTemplate upserts into the
+// structure_templates collection.
+type storedTemplate struct {
+	TemplateKey string                   `bson:"template_key"`
+	Documents   []map[string]interface{} `bson:"documents"`
+}
+
+// ForkStructureTemplate clones sourceTemplateKey's stored documents under
+// newTemplateKey, assigning each document a freshly generated _id and the
+// copy a fresh updated_at. It never touches any client database - unlike
+// ApplyStructureTemplate, this only branches a template definition so it can
+// be edited independently before being rolled out.
+func ForkStructureTemplate(ctx context.Context, sourceTemplateKey string, newTemplateKey string) (*TemplateApplySummary, error) {
+	if sourceTemplateKey == "" {
+		return nil, NewError(ErrValidation, fmt.Errorf("sourceTemplateKey is required"))
+	}
+	if newTemplateKey == "" {
+		return nil, NewError(ErrValidation, fmt.Errorf("newTemplateKey is required"))
+	}
+	if sourceTemplateKey == newTemplateKey {
+		return nil, NewError(ErrValidation, fmt.Errorf("newTemplateKey must differ from sourceTemplateKey"))
+	}
+
+	if db.MongoClient == nil {
+		return nil, NewError(ErrTransient, fmt.Errorf("mongo client is not initialized"))
+	}
+
+	templatesColl := db.MongoClient.Database(resolveCoreDBName()).Collection("structure_templates")
+
+	var source storedTemplate
+	if err := templatesColl.FindOne(ctx, bson.M{"template_key": sourceTemplateKey}).Decode(&source); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, NewError(ErrNotFound, fmt.Errorf("template %q does not exist", sourceTemplateKey))
+		}
+		return nil, mapMongoError(fmt.Errorf("failed to load template %q: %w", sourceTemplateKey, err))
+	}
+
+	existing, err := templatesColl.CountDocuments(ctx, bson.M{"template_key": newTemplateKey})
+	if err != nil {
+		return nil, mapMongoError(fmt.Errorf("failed to check template %q: %w", newTemplateKey, err))
+	}
+	if existing > 0 {
+		return nil, NewError(ErrDuplicate, fmt.Errorf("template %q already exists", newTemplateKey))
+	}
+
+	forkedDocs := make([]map[string]interface{}, 0, len(source.Documents))
+	for idx, doc := range source.Documents {
+		clone := cloneMap(doc)
+		delete(clone, "_id")
+
+		sanitized, _, err := prepareTemplateDocument(clone)
+		if err != nil {
+			return nil, NewError(ErrInvalidID, fmt.Errorf("document %d: %w", idx, err))
+		}
+		forkedDocs = append(forkedDocs, sanitized)
+	}
+
+	_, err = templatesColl.UpdateOne(
+		ctx,
+		bson.M{"template_key": newTemplateKey},
+		bson.M{"$set": bson.M{
+			"template_key": newTemplateKey,
+			"documents":    forkedDocs,
+			"updated_at":   time.Now(),
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return nil, mapMongoError(fmt.Errorf("failed to upsert forked template %q: %w", newTemplateKey, err))
+	}
+
+	return &TemplateApplySummary{
+		TemplateKey:      newTemplateKey,
+		UpdatedDocuments: len(forkedDocs),
+	}, nil
+}
@@ -0,0 +1,199 @@
+package structure_websites
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"w100n_backend_core/core/db"
+)
+
+// TemplateSnapshot records one client's structure_websites document set
+// immediately before and after a single ApplyStructureTemplate rollout, so
+// RollbackStructureTemplate can restore it later. All snapshots written by
+// the same ApplyStructureTemplate call share SnapshotID.
+type TemplateSnapshot struct {
+	SnapshotID        string                   `bson:"snapshot_id"`
+	TemplateKey       string                   `bson:"template_key"`
+	ClientName        string                   `bson:"client_name"`
+	AppliedAt         time.Time                `bson:"applied_at"`
+	PreviousDocuments []map[string]interface{} `bson:"previous_documents"`
+	AppliedDocuments  []map[string]interface{} `bson:"applied_documents"`
+}
+
+// fetchClientDocuments reads every document currently in clientDB's
+// structure_websites collection, for use as a rollback snapshot's
+// "previous" state.
+func fetchClientDocuments(ctx context.Context, clientDB *mongo.Database) ([]map[string]interface{}, error) {
+	collection := clientDB.Collection("structure_websites")
+
+	cursor, err := collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, mapMongoError(fmt.Errorf("failed to query existing documents: %w", err))
+	}
+	defer cursor.Close(ctx)
+
+	var documents []map[string]interface{}
+	for cursor.Next(ctx) {
+		var doc map[string]interface{}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, NewError(ErrDecode, fmt.Errorf("failed to decode existing document: %w", err))
+		}
+		documents = append(documents, doc)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, mapMongoError(fmt.Errorf("cursor error while reading existing documents: %w", err))
+	}
+	return documents, nil
+}
+
+// documentIDs collects the _id of each document that has one, preserving
+// order. Documents without a decodable _id are skipped.
+func documentIDs(docs []map[string]interface{}) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(docs))
+	for _, doc := range docs {
+		if id, ok := doc["_id"].(primitive.ObjectID); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// recordTemplateSnapshot persists clientName's before/after document set for
+// one ApplyStructureTemplate rollout, then prunes snapshots for templateKey
+// beyond retention (retention <= 0 keeps every snapshot).
+func recordTemplateSnapshot(ctx context.Context, coreDatabase *mongo.Database, snapshotID, templateKey, clientName string, previousDocs, appliedDocs []map[string]interface{}, retention int) error {
+	snapshotsColl := coreDatabase.Collection("structure_template_snapshots")
+
+	_, err := snapshotsColl.InsertOne(ctx, TemplateSnapshot{
+		SnapshotID:        snapshotID,
+		TemplateKey:       templateKey,
+		ClientName:        clientName,
+		AppliedAt:         time.Now(),
+		PreviousDocuments: previousDocs,
+		AppliedDocuments:  appliedDocs,
+	})
+	if err != nil {
+		return mapMongoError(fmt.Errorf("failed to insert snapshot: %w", err))
+	}
+
+	if retention <= 0 {
+		return nil
+	}
+
+	return pruneTemplateSnapshots(ctx, snapshotsColl, templateKey, retention)
+}
+
+// pruneTemplateSnapshots deletes the snapshot_id groups for templateKey
+// beyond the most recent `retention` groups (ordered by applied_at), so
+// storage stays bounded for templates that are rolled out repeatedly.
+func pruneTemplateSnapshots(ctx context.Context, snapshotsColl *mongo.Collection, templateKey string, retention int) error {
+	cursor, err := snapshotsColl.Find(
+		ctx,
+		bson.M{"template_key": templateKey},
+		options.Find().SetProjection(bson.M{"snapshot_id": 1, "applied_at": 1}).SetSort(bson.M{"applied_at": -1}),
+	)
+	if err != nil {
+		return mapMongoError(fmt.Errorf("failed to list snapshots for retention: %w", err))
+	}
+	defer cursor.Close(ctx)
+
+	seen := make(map[string]struct{})
+	var ordered []string
+	for cursor.Next(ctx) {
+		var doc struct {
+			SnapshotID string `bson:"snapshot_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return NewError(ErrDecode, fmt.Errorf("failed to decode snapshot for retention: %w", err))
+		}
+		if _, ok := seen[doc.SnapshotID]; ok {
+			continue
+		}
+		seen[doc.SnapshotID] = struct{}{}
+		ordered = append(ordered, doc.SnapshotID)
+	}
+	if err := cursor.Err(); err != nil {
+		return mapMongoError(fmt.Errorf("cursor error while listing snapshots for retention: %w", err))
+	}
+
+	if len(ordered) <= retention {
+		return nil
+	}
+
+	stale := ordered[retention:]
+	if _, err := snapshotsColl.DeleteMany(ctx, bson.M{"template_key": templateKey, "snapshot_id": bson.M{"$in": stale}}); err != nil {
+		return mapMongoError(fmt.Errorf("failed to prune stale snapshots: %w", err))
+	}
+	return nil
+}
+
+// RollbackStructureTemplate restores every client database recorded under
+// snapshotID to its pre-rollout structure_websites state, by reading each
+// client's TemplateSnapshot.PreviousDocuments and replacing its current
+// documents with them via applyTemplateToClient.
+func RollbackStructureTemplate(ctx context.Context, snapshotID string) (*TemplateApplySummary, error) {
+	if snapshotID == "" {
+		return nil, NewError(ErrValidation, fmt.Errorf("snapshotID is required"))
+	}
+	if db.MongoClient == nil {
+		return nil, NewError(ErrTransient, fmt.Errorf("mongo client is not initialized"))
+	}
+
+	coreDatabase := db.MongoClient.Database(resolveCoreDBName())
+	snapshotsColl := coreDatabase.Collection("structure_template_snapshots")
+
+	cursor, err := snapshotsColl.Find(ctx, bson.M{"snapshot_id": snapshotID})
+	if err != nil {
+		return nil, mapMongoError(fmt.Errorf("failed to query snapshots %q: %w", snapshotID, err))
+	}
+	defer cursor.Close(ctx)
+
+	var snapshots []TemplateSnapshot
+	for cursor.Next(ctx) {
+		var snap TemplateSnapshot
+		if err := cursor.Decode(&snap); err != nil {
+			return nil, NewError(ErrDecode, fmt.Errorf("failed to decode snapshot: %w", err))
+		}
+		snapshots = append(snapshots, snap)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, mapMongoError(fmt.Errorf("cursor error while reading snapshots: %w", err))
+	}
+	if len(snapshots) == 0 {
+		return nil, NewError(ErrNotFound, fmt.Errorf("no snapshots found for snapshot id %q", snapshotID))
+	}
+
+	summary := &TemplateApplySummary{
+		TemplateKey: snapshots[0].TemplateKey,
+		Mode:        RolloutModeBestEffort,
+		SnapshotID:  snapshotID,
+	}
+
+	for _, snap := range snapshots {
+		clientDB := db.MongoClient.Database(snap.ClientName)
+		restored, deleted, err := applyTemplateToClient(ctx, clientDB, snap.PreviousDocuments, documentIDs(snap.PreviousDocuments))
+		if err != nil {
+			return nil, fmt.Errorf("client %s: %w", snap.ClientName, mapMongoError(err))
+		}
+
+		summary.UpdatedDocuments += restored
+		summary.DeletedDocuments += deleted
+		summary.ClientNames = append(summary.ClientNames, snap.ClientName)
+		summary.ClientResults = append(summary.ClientResults, ClientRolloutResult{
+			ClientName:       snap.ClientName,
+			Success:          true,
+			UpdatedDocuments: restored,
+			DeletedDocuments: deleted,
+		})
+	}
+
+	sort.Strings(summary.ClientNames)
+	return summary, nil
+}